@@ -2,23 +2,25 @@ package main
 
 import (
 	"log"
-	"os"
+
 	"the_knight/internal/web"
 )
 
 func main() {
-	server := web.NewServer()
+	// PORT, KIOSK, WORKERS, WORKER, WORKER_TOKEN, WORKER_SLOTS and DATA_DIR
+	cfg, err := web.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
-	// Get port from environment variable, default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	server := web.NewServer()
+	if err := server.Configure(cfg); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
 	// Start server
-	addr := ":" + port
-	log.Printf("Starting Knight's Tour server on %s", addr)
-	if err := server.Start(addr); err != nil {
+	log.Printf("Starting Knight's Tour server on %s", cfg.Addr)
+	if err := server.Start(cfg.Addr); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
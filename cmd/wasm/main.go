@@ -0,0 +1,122 @@
+//go:build js && wasm
+
+// Command wasm exposes the solver core to the browser.
+// Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o web/static/knight.wasm ./cmd/wasm
+//
+// It registers a global `knight` object with a single method:
+//
+//	knight.solve(size, startX, startY, options) -> Promise<result>
+//
+// where options is an optional object {maxAttempts, timeoutMs, onMove}.
+package main
+
+import (
+	"context"
+	"fmt"
+	"syscall/js"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("solve", js.FuncOf(solve))
+	js.Global().Set("knight", api)
+
+	// Keep the Go runtime alive so the exported functions stay callable.
+	select {}
+}
+
+// solve is the JS entry point. It returns a Promise because the search may take
+// a while and must not run on the JavaScript event loop.
+func solve(this js.Value, args []js.Value) any {
+	handler := js.FuncOf(func(this js.Value, promiseArgs []js.Value) any {
+		resolve, reject := promiseArgs[0], promiseArgs[1]
+
+		go func() {
+			result, err := run(args)
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+				return
+			}
+			resolve.Invoke(result)
+		}()
+		return nil
+	})
+	defer handler.Release()
+
+	return js.Global().Get("Promise").New(handler)
+}
+
+// run parses the JS arguments, solves and converts the result into a JS object.
+func run(args []js.Value) (js.Value, error) {
+	if len(args) < 3 {
+		return js.Undefined(), fmt.Errorf("solve(size, startX, startY, options): expected at least 3 arguments, got %d", len(args))
+	}
+
+	size := args[0].Int()
+	// The web server enforces the same limit
+	if size <= 0 || size > solver.MaxBoardSize {
+		return js.Undefined(), fmt.Errorf("board size must be between 1 and %d", solver.MaxBoardSize)
+	}
+	start := board.Position{X: args[1].Int(), Y: args[2].Int()}
+	if start.X < 0 || start.X >= size || start.Y < 0 || start.Y >= size {
+		return js.Undefined(), fmt.Errorf("start position (%d, %d) is off the board", start.X, start.Y)
+	}
+
+	ctx := context.Background()
+	var opts solver.SolveOptions
+	if len(args) > 3 && args[3].Type() == js.TypeObject {
+		jsOpts := args[3]
+		if v := jsOpts.Get("maxAttempts"); v.Type() == js.TypeNumber {
+			opts.MaxAttempts = v.Int()
+		}
+		if v := jsOpts.Get("timeoutMs"); v.Type() == js.TypeNumber && v.Int() > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(v.Int())*time.Millisecond)
+			defer cancel()
+		}
+		if v := jsOpts.Get("onMove"); v.Type() == js.TypeFunction {
+			opts.OnMove = func(update solver.MoveUpdate) {
+				v.Invoke(moveToJS(update))
+			}
+		}
+	}
+
+	result, err := solver.NewSolver().SolveWithOptions(ctx, size, start, opts)
+	if err != nil && err != solver.ErrAttemptLimit && err != context.DeadlineExceeded {
+		return js.Undefined(), err
+	}
+
+	moves := make([]any, len(result.Moves))
+	for i, move := range result.Moves {
+		moves[i] = moveToJS(move)
+	}
+
+	return js.ValueOf(map[string]any{
+		"success":      result.Success,
+		"attemptCount": result.AttemptCount,
+		"deadEnds":     result.DeadEnds,
+		"limitReached": err == solver.ErrAttemptLimit,
+		"timedOut":     err == context.DeadlineExceeded,
+		"moves":        moves,
+	}), nil
+}
+
+// moveToJS converts a MoveUpdate into the same shape the server sends over SSE.
+func moveToJS(update solver.MoveUpdate) js.Value {
+	return js.ValueOf(map[string]any{
+		"Position": map[string]any{
+			"X": update.Position.X,
+			"Y": update.Position.Y,
+		},
+		"MoveNumber":      update.MoveNumber,
+		"IsBacktrack":     update.IsBacktrack,
+		"IsDeadEnd":       update.IsDeadEnd,
+		"CandidatesTried": update.CandidatesTried,
+	})
+}
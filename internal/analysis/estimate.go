@@ -0,0 +1,298 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// Difficulty classes of an estimate, by score.
+const (
+	DifficultyTrivial    = "trivial"  // Warnsdorff walks straight through
+	DifficultyEasy       = "easy"     // some backtracking
+	DifficultyModerate   = "moderate" // noticeable search
+	DifficultyHard       = "hard"     // expect a long search, or none that finishes
+	DifficultyImpossible = "impossible"
+)
+
+// DefaultEstimateSamples is the number of restarts with other seeds an estimate runs.
+const DefaultEstimateSamples = 8
+
+// MaxEstimateSamples bounds the restarts a caller may ask for.
+const MaxEstimateSamples = 32
+
+// Attempt budget of every sampled run: sampleBudgetPerSquare per square, at most maxSampleBudget.
+const (
+	sampleBudgetPerSquare = 20
+	maxSampleBudget       = 200_000
+)
+
+// DegreeStats describes how many knight moves the squares of the empty board have.
+type DegreeStats struct {
+	Min  int     `json:"min"`
+	Mean float64 `json:"mean"`
+	// Histogram counts the squares by number of moves (0 to 8)
+	Histogram [9]int `json:"histogram"`
+	// LowDegree counts the squares with at most two moves. A tour has to
+	// pass through them using their only neighbours, which is where searches
+	// get stuck and backtrack.
+	LowDegree int `json:"lowDegree"`
+}
+
+// Sample is one bounded run of an estimate.
+type Sample struct {
+	Seed     int64 `json:"seed"`
+	Solved   bool  `json:"solved"`
+	Attempts int   `json:"attempts"`
+}
+
+// EstimateReport predicts how hard a solve will be before it is launched.
+type EstimateReport struct {
+	// Score runs from 0 (trivial) to 100 (no tour exists, or no run found one)
+	Score      float64 `json:"score"`
+	Difficulty string  `json:"difficulty"`
+	// Feasible tells whether a tour exists from the start square, as far as
+	// parity and degree arguments (and known theorems for square boards) can
+	// tell; any run that finds a tour settles it
+	Feasible Existence   `json:"feasible"`
+	Squares  int         `json:"squares"`
+	Degrees  DegreeStats `json:"degrees"`
+	// Requested is the run with the requested options, seed included. Solves
+	// are deterministic, so the real solve repeats it.
+	Requested Sample `json:"requested"`
+	// Samples are restarts with other seeds; they show how much the outcome depends on luck
+	Samples      []Sample `json:"samples"`
+	SampleBudget int      `json:"sampleBudget"`
+	// ExpectedAttempts predicts the attempts of the solve. When the requested
+	// run did not finish within the budget it is a lower bound (LowerBound).
+	ExpectedAttempts int  `json:"expectedAttempts"`
+	LowerBound       bool `json:"lowerBound,omitempty"`
+	// ExpectedMs converts ExpectedAttempts to time at the speed of the sampled runs
+	ExpectedMs int64 `json:"expectedMs"`
+	// Reasons explain the estimate in plain sentences
+	Reasons []string `json:"reasons"`
+}
+
+// Estimate scores how hard solving from start with opts is expected to be,
+// from the degree distribution of the board, the parity of its colors and
+// a few runs with a bounded attempt budget: the requested run itself plus
+// samples restarts with other seeds (0 = DefaultEstimateSamples).
+func Estimate(ctx context.Context, size int, start board.Position, opts solver.SolveOptions, samples int) (*EstimateReport, error) {
+	b, err := opts.Board(size)
+	if err != nil {
+		return nil, err
+	}
+	if !b.Contains(start) {
+		return nil, fmt.Errorf("start (%d, %d) is not on the board", start.X, start.Y)
+	}
+	if samples <= 0 {
+		samples = DefaultEstimateSamples
+	}
+	samples = min(samples, MaxEstimateSamples)
+
+	est := &EstimateReport{Squares: b.SquareCount(), Degrees: degreeStats(b)}
+	est.Reasons = append(est.Reasons, fmt.Sprintf("The board has %d squares; %d of them have at most two knight moves", est.Squares, est.Degrees.LowDegree))
+
+	var reason string
+	est.Feasible, reason = instanceFeasibility(b, size, start, opts)
+	if reason != "" {
+		est.Reasons = append(est.Reasons, reason)
+	}
+	if est.Feasible == DoesNotExist {
+		est.Score, est.Difficulty = 100, DifficultyImpossible
+		return est, nil
+	}
+
+	// Bounded runs: the requested one, then restarts with other seeds
+	est.SampleBudget = min(sampleBudgetPerSquare*est.Squares, maxSampleBudget)
+	if opts.MaxAttempts > 0 {
+		est.SampleBudget = min(est.SampleBudget, opts.MaxAttempts)
+	}
+	opts.MaxAttempts = est.SampleBudget
+	opts.StreamMoves, opts.RecordTree, opts.OnMove, opts.OnLog = false, false, nil, nil
+
+	began := time.Now()
+	totalAttempts := 0
+	run := func(seed int64) (Sample, error) {
+		opts.Seed = seed
+		result, err := solver.NewSolver().SolveWithOptions(ctx, size, start, opts)
+		if err != nil && err != solver.ErrAttemptLimit {
+			return Sample{}, err
+		}
+		totalAttempts += result.AttemptCount
+		return Sample{Seed: seed, Solved: result.Success, Attempts: result.AttemptCount}, nil
+	}
+
+	requestedSeed := opts.Seed
+	if est.Requested, err = run(requestedSeed); err != nil {
+		return nil, err
+	}
+	// The restart seeds derive from the requested one; seed 0 would mean
+	// the fixed move order rather than a random restart
+	seeds := rand.New(rand.NewSource(requestedSeed))
+	var solved []int
+	for i := 1; i <= samples; i++ {
+		sample, err := run(seeds.Int63() | 1)
+		if err != nil {
+			return nil, err
+		}
+		est.Samples = append(est.Samples, sample)
+		if sample.Solved {
+			solved = append(solved, sample.Attempts)
+		}
+	}
+
+	// A run that found a tour settles that one exists
+	if est.Requested.Solved || len(solved) > 0 {
+		est.Feasible = Exists
+	}
+	if est.Requested.Solved {
+		est.ExpectedAttempts = est.Requested.Attempts
+		est.Reasons = append(est.Reasons, fmt.Sprintf("The requested run finds a tour in %d attempts", est.Requested.Attempts))
+	} else {
+		est.ExpectedAttempts, est.LowerBound = est.SampleBudget, true
+		est.Reasons = append(est.Reasons, fmt.Sprintf("The requested run finds no tour within %d attempts", est.SampleBudget))
+	}
+	if len(solved) > 0 {
+		sort.Ints(solved)
+		if est.LowerBound {
+			// Restarting until a seed succeeds costs about the median run
+			// divided by the success rate; the requested run is no cheaper
+			// than the budget it exhausted
+			est.ExpectedAttempts = max(est.SampleBudget, solved[len(solved)/2]*samples/len(solved))
+		}
+		est.Reasons = append(est.Reasons, fmt.Sprintf("%d of %d restarts with other seeds find a tour (median %d attempts)",
+			len(solved), samples, solved[len(solved)/2]))
+	} else {
+		est.Reasons = append(est.Reasons, fmt.Sprintf("None of %d restarts with other seeds finds a tour within %d attempts", samples, est.SampleBudget))
+	}
+
+	if elapsed := time.Since(began); totalAttempts > 0 {
+		perAttempt := float64(elapsed) / float64(totalAttempts)
+		est.ExpectedMs = int64(perAttempt * float64(est.ExpectedAttempts) / float64(time.Millisecond))
+	}
+
+	// Attempts beyond one per square are backtracking: every factor of ten
+	// adds 20 points, and squares with few moves add up to 20 more
+	lowShare := float64(est.Degrees.LowDegree) / float64(est.Squares)
+	est.Score = 20*math.Log10(float64(max(est.ExpectedAttempts, est.Squares))/float64(est.Squares)) + 20*lowShare
+	if est.LowerBound && len(solved) == 0 {
+		est.Score = max(est.Score, 80)
+	}
+	est.Score = math.Round(min(est.Score, 100)*10) / 10
+	est.Difficulty = difficulty(est.Score)
+	return est, nil
+}
+
+func difficulty(score float64) string {
+	switch {
+	case score < 10:
+		return DifficultyTrivial
+	case score < 30:
+		return DifficultyEasy
+	case score < 55:
+		return DifficultyModerate
+	}
+	return DifficultyHard
+}
+
+// degreeStats counts the knight moves of every square of the empty board.
+func degreeStats(b board.Board) DegreeStats {
+	stats := DegreeStats{Min: 8}
+	total := 0
+	for x := range b {
+		for y := range b[x] {
+			pos := board.Position{X: x, Y: y}
+			if !b.Contains(pos) {
+				continue
+			}
+			d := b.CountValidMoves(pos)
+			stats.Histogram[d]++
+			stats.Min = min(stats.Min, d)
+			total += d
+			if d <= 2 {
+				stats.LowDegree++
+			}
+		}
+	}
+	if squares := b.SquareCount(); squares > 0 {
+		stats.Mean = math.Round(float64(total)/float64(squares)*100) / 100
+	}
+	return stats
+}
+
+// instanceFeasibility settles what can be decided without searching, with
+// a reason for the answer:
+//   - square boards: the rules of the heat report (see feasibility);
+//   - any board: a tour alternates colors, so the color counts may differ
+//     by at most one (none for a closed tour), and an odd board must start
+//     on the majority color;
+//   - any board: a square without moves cannot be visited, a closed tour
+//     needs two moves at every square, and squares with a single move must
+//     be ends of an open one: besides the start, at most one may exist.
+func instanceFeasibility(b board.Board, size int, start board.Position, opts solver.SolveOptions) (Existence, string) {
+	squares := b.SquareCount()
+	if len(opts.Shape) == 0 {
+		if answer, ok := feasibility(size, start, opts.Closed); ok {
+			if answer == Exists {
+				return answer, fmt.Sprintf("Known results for %dx%d boards guarantee a tour from this start square", size, size)
+			}
+			return answer, fmt.Sprintf("Known results for %dx%d boards rule out a tour from this start square", size, size)
+		}
+	}
+	if squares == 1 {
+		if opts.Closed {
+			return DoesNotExist, "A single square has no move to close a tour"
+		}
+		return Exists, "A single square is a tour by itself"
+	}
+
+	colors := [2]int{}
+	for x := range b {
+		for y := range b[x] {
+			if b.Contains(board.Position{X: x, Y: y}) {
+				colors[(x+y)%2]++
+			}
+		}
+	}
+	startColor := (start.X + start.Y) % 2
+	switch diff := colors[0] - colors[1]; {
+	case opts.Closed && diff != 0:
+		return DoesNotExist, fmt.Sprintf("A closed tour alternates colors, but the board has %d and %d squares of each", colors[0], colors[1])
+	case diff > 1 || diff < -1:
+		return DoesNotExist, fmt.Sprintf("A tour alternates colors, but the board has %d and %d squares of each", colors[0], colors[1])
+	case diff != 0 && colors[startColor] < colors[1-startColor]:
+		return DoesNotExist, "The board has an odd number of squares, so a tour must start on the majority color"
+	}
+
+	single := 0
+	for x := range b {
+		for y := range b[x] {
+			pos := board.Position{X: x, Y: y}
+			if !b.Contains(pos) {
+				continue
+			}
+			switch d := b.CountValidMoves(pos); {
+			case d == 0:
+				return DoesNotExist, fmt.Sprintf("Square (%d, %d) has no knight moves", x, y)
+			case d == 1 && opts.Closed:
+				return DoesNotExist, fmt.Sprintf("Square (%d, %d) has a single knight move, but a closed tour needs two", x, y)
+			case d == 1 && pos != start:
+				single++
+			}
+		}
+	}
+	// A square with a single move must be an end of the tour. The start is
+	// one end, so at most one other square may have a single move, whether or
+	// not the start has one too.
+	if single > 1 {
+		return DoesNotExist, "Too many squares with a single knight move: only the two ends of a tour can have one, and one end is the start"
+	}
+	return UnknownExists, ""
+}
@@ -0,0 +1,38 @@
+package analysis
+
+import (
+	"context"
+	"testing"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+func TestEstimateSolvedRunSettlesFeasibility(t *testing.T) {
+	// No theorem covers composite boards, so only a run can tell
+	rects, err := board.ShapeRects("L", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Seed -1 used to restart with seed 0, the fixed move order
+	opts := solver.SolveOptions{Shape: rects, Seed: -1}
+	est, err := Estimate(context.Background(), 5, board.Position{X: 5, Y: 5}, opts, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solved := est.Requested.Solved
+	for _, sample := range est.Samples {
+		solved = solved || sample.Solved
+	}
+	if !solved {
+		t.Fatalf("no run found a tour: %+v", est)
+	}
+	if est.Feasible != Exists {
+		t.Errorf("Feasible = %q after a run found a tour, want %q", est.Feasible, Exists)
+	}
+	for _, sample := range est.Samples {
+		if sample.Seed == 0 {
+			t.Errorf("sample restarted with seed 0, the fixed move order")
+		}
+	}
+}
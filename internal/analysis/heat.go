@@ -0,0 +1,200 @@
+// Package analysis answers questions about knight's tour instances without
+// streaming a solve: which start squares admit tours, how hard an instance is.
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// Existence is the answer to "does a tour exist from this square?".
+type Existence string
+
+const (
+	Exists        Existence = "yes"
+	DoesNotExist  Existence = "no"
+	UnknownExists Existence = "unknown" // the search budget ran out
+)
+
+// Source tells how an answer was obtained.
+type Source string
+
+const (
+	SourceRule   Source = "rule"   // a known theorem or parity argument
+	SourceSearch Source = "search" // a solver run on this request
+	SourceCache  Source = "cache"  // a solver run from an earlier request
+)
+
+// MaxHeatSize bounds the board size of a heat report.
+const MaxHeatSize = 20
+
+// DefaultHeatBudget is the attempt budget of every search in a heat report.
+const DefaultHeatBudget = 2_000_000
+
+// SquareReport holds the answers for one start square.
+type SquareReport struct {
+	Position     board.Position `json:"position"`
+	Open         Existence      `json:"open"`
+	OpenSource   Source         `json:"openSource"`
+	Closed       Existence      `json:"closed"`
+	ClosedSource Source         `json:"closedSource"`
+}
+
+// HeatReport lists, for every start square of a size x size board, whether
+// open and closed tours exist from it.
+type HeatReport struct {
+	Size        int              `json:"size"`
+	Squares     [][]SquareReport `json:"squares"`
+	OpenCount   int              `json:"openCount"`
+	ClosedCount int              `json:"closedCount"`
+	Unknown     int              `json:"unknown"`
+}
+
+// heatKey identifies a cached search result.
+type heatKey struct {
+	size   int
+	pos    board.Position
+	closed bool
+}
+
+// heatCache remembers search results across reports; answers never change.
+var heatCache = struct {
+	sync.Mutex
+	results map[heatKey]Existence
+}{results: make(map[heatKey]Existence)}
+
+// Heat builds the report for a size x size board. Squares equivalent under
+// the board's symmetries share one answer, rules settle what they can and
+// the remaining squares are searched with the given attempt budget per square.
+func Heat(ctx context.Context, size, budget int) (*HeatReport, error) {
+	if size <= 0 || size > MaxHeatSize {
+		return nil, fmt.Errorf("board size must be between 1 and %d", MaxHeatSize)
+	}
+	if budget <= 0 {
+		budget = DefaultHeatBudget
+	}
+
+	report := &HeatReport{Size: size, Squares: make([][]SquareReport, size)}
+	for x := 0; x < size; x++ {
+		report.Squares[x] = make([]SquareReport, size)
+		for y := 0; y < size; y++ {
+			pos := board.Position{X: x, Y: y}
+			canonical := canonicalSquare(size, pos)
+
+			sq := SquareReport{Position: pos}
+			var err error
+			if sq.Open, sq.OpenSource, err = tourExists(ctx, size, canonical, false, budget); err != nil {
+				return nil, err
+			}
+			if sq.Closed, sq.ClosedSource, err = tourExists(ctx, size, canonical, true, budget); err != nil {
+				return nil, err
+			}
+
+			if sq.Open == Exists {
+				report.OpenCount++
+			}
+			if sq.Closed == Exists {
+				report.ClosedCount++
+			}
+			if sq.Open == UnknownExists || sq.Closed == UnknownExists {
+				report.Unknown++
+			}
+			report.Squares[x][y] = sq
+		}
+	}
+	return report, nil
+}
+
+// tourExists answers for one (canonical) start square, using the feasibility
+// rules first, then the cache, then a bounded search.
+func tourExists(ctx context.Context, size int, pos board.Position, closed bool, budget int) (Existence, Source, error) {
+	if answer, ok := feasibility(size, pos, closed); ok {
+		return answer, SourceRule, nil
+	}
+
+	key := heatKey{size: size, pos: pos, closed: closed}
+	heatCache.Lock()
+	answer, ok := heatCache.results[key]
+	heatCache.Unlock()
+	if ok {
+		return answer, SourceCache, nil
+	}
+
+	result, err := solver.NewSolver().SolveWithOptions(ctx, size, pos, solver.SolveOptions{
+		Closed:      closed,
+		MaxAttempts: budget,
+	})
+	switch {
+	case err == solver.ErrAttemptLimit:
+		// Not cached: a later report may run with a larger budget
+		return UnknownExists, SourceSearch, nil
+	case err != nil:
+		return "", "", err
+	case result.Success:
+		answer = Exists
+	default:
+		// The search is exhaustive, so failing within the budget is a proof
+		answer = DoesNotExist
+	}
+
+	heatCache.Lock()
+	heatCache.results[key] = answer
+	heatCache.Unlock()
+	return answer, SourceSearch, nil
+}
+
+// feasibility settles the cases decided by known results:
+//   - 1x1: the single square is an open tour; there is no move to close it.
+//   - 2x2 to 4x4: no tours at all.
+//   - closed tours (Schwenk): exist iff n is even and n >= 6, and then from every
+//     square, since a closed tour is a cycle through all of them.
+//   - open tours on even n >= 6 exist everywhere (cut any closed tour).
+//   - odd n: a tour alternates colors and has an odd number of squares, so it
+//     starts and ends on the majority color; minority squares admit none.
+func feasibility(size int, pos board.Position, closed bool) (Existence, bool) {
+	switch {
+	case size == 1:
+		if closed {
+			return DoesNotExist, true
+		}
+		return Exists, true
+	case size <= 4:
+		return DoesNotExist, true
+	case closed:
+		if size%2 == 0 {
+			return Exists, true
+		}
+		return DoesNotExist, true
+	case size%2 == 0:
+		return Exists, true
+	case (pos.X+pos.Y)%2 != 0:
+		return DoesNotExist, true
+	}
+	return "", false
+}
+
+// canonicalSquare maps a square to the representative of its orbit under the
+// 8 symmetries of the square board, so equivalent squares share a cache entry.
+func canonicalSquare(size int, pos board.Position) board.Position {
+	best := pos
+	for _, p := range symmetricSquares(size, pos) {
+		if p.X < best.X || (p.X == best.X && p.Y < best.Y) {
+			best = p
+		}
+	}
+	return best
+}
+
+// symmetricSquares returns the images of pos under the rotations and reflections of the board.
+func symmetricSquares(size int, pos board.Position) []board.Position {
+	m := size - 1
+	x, y := pos.X, pos.Y
+	return []board.Position{
+		{X: x, Y: y}, {X: y, Y: m - x}, {X: m - x, Y: m - y}, {X: m - y, Y: x},
+		{X: y, Y: x}, {X: x, Y: m - y}, {X: m - y, Y: m - x}, {X: m - x, Y: y},
+	}
+}
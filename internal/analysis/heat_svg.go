@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// heatCell is the size of one square in the SVG heatmap, in pixels.
+const heatCell = 40
+
+// heatClass describes how a square is drawn in the heatmap.
+type heatClass struct {
+	label string
+	fill  string
+	text  string
+}
+
+var (
+	heatClosed  = heatClass{"C", "#1b7f3b", "closed and open tours"}
+	heatOpen    = heatClass{"O", "#7fc97f", "open tours only"}
+	heatNone    = heatClass{"-", "#5a5a66", "no tour"}
+	heatUnknown = heatClass{"?", "#e6b422", "unknown (budget exhausted)"}
+)
+
+// classify picks the heatmap class of a square.
+func classify(sq SquareReport) heatClass {
+	switch {
+	case sq.Open == UnknownExists || sq.Closed == UnknownExists:
+		return heatUnknown
+	case sq.Closed == Exists:
+		return heatClosed
+	case sq.Open == Exists:
+		return heatOpen
+	default:
+		return heatNone
+	}
+}
+
+// Grid renders the report as text, one character per square (see SVG for the legend).
+func (r *HeatReport) Grid() string {
+	var buf bytes.Buffer
+	for _, row := range r.Squares {
+		for y, sq := range row {
+			if y > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(classify(sq).label)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// SVG renders the report as a heatmap with a legend.
+func (r *HeatReport) SVG() []byte {
+	margin := heatCell / 2
+	boardPx := r.Size * heatCell
+	legend := []heatClass{heatClosed, heatOpen, heatNone, heatUnknown}
+	width := boardPx + 2*margin
+	height := boardPx + 2*margin + len(legend)*20 + 10
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&buf, `<title>Knight's tour start squares on a %dx%d board</title>`+"\n", r.Size, r.Size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#0a0a0f"/>`+"\n", width, height)
+
+	for x, row := range r.Squares {
+		for y, sq := range row {
+			class := classify(sq)
+			px := margin + y*heatCell
+			py := margin + x*heatCell
+			fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#0a0a0f"><title>(%d, %d): open %s, closed %s</title></rect>`+"\n",
+				px, py, heatCell, heatCell, class.fill, x, y, sq.Open, sq.Closed)
+			fmt.Fprintf(&buf, `<text x="%d" y="%d" fill="#ffffff" font-size="14" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+				px+heatCell/2, py+heatCell/2, class.label)
+		}
+	}
+
+	for i, class := range legend {
+		y := boardPx + 2*margin + i*20
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="14" height="14" fill="%s"/>`+"\n", margin, y, class.fill)
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" fill="#e0b0ff" font-size="12" dominant-baseline="hanging">%s  %s</text>`+"\n",
+			margin+20, y+1, class.label, class.text)
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
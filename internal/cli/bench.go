@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// benchMode is one solver configuration measured by the bench command.
+type benchMode struct {
+	name string
+	opts solver.SolveOptions
+}
+
+// runBench times repeated solves of the same board with the incremental
+// degree cache and with the neighbour rescan it replaces, and reports the
+// heap allocations and garbage collections each solve causes.
+func runBench(args []string) error {
+	fs := newFlagSet("bench", "")
+	size := fs.Int("size", 50, messages.T("cli.flagBoardSize"))
+	x := fs.Int("x", 0, messages.T("cli.flagX"))
+	y := fs.Int("y", 0, messages.T("cli.flagY"))
+	runs := fs.Int("runs", 5, messages.T("cli.flagRuns"))
+	maxAttempts := fs.Int("max-attempts", 1_000_000, messages.T("cli.flagMaxAttempts"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *size <= 0 {
+		return messages.Errorf("cli.invalidSize", *size)
+	}
+	if *runs <= 0 {
+		return messages.Errorf("cli.invalidRuns", *runs)
+	}
+	start := board.Position{X: *x, Y: *y}
+
+	modes := []benchMode{
+		{"scan", solver.SolveOptions{MaxAttempts: *maxAttempts, ScanDegrees: true}},
+		{"cached", solver.SolveOptions{MaxAttempts: *maxAttempts}},
+	}
+
+	fmt.Printf("%s\n\n", messages.T("cli.benchHeader", *size, *size, start.X, start.Y, *runs))
+	fmt.Printf("%-8s %10s %8s %14s %9s %13s %13s %7s\n",
+		messages.T("cli.benchMode"), messages.T("cli.benchAttempts"), messages.T("cli.benchSolved"), messages.T("cli.benchTime"),
+		messages.T("cli.benchSpeedup"), messages.T("cli.benchAllocs"), messages.T("cli.benchBytes"), messages.T("cli.benchGCs"))
+
+	var baseline time.Duration
+	for i, mode := range modes {
+		var total time.Duration
+		var result *solver.SolveResult
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		for r := 0; r < *runs; r++ {
+			started := time.Now()
+			var err error
+			result, err = solver.NewSolver().SolveWithOptions(context.Background(), *size, start, mode.opts)
+			total += time.Since(started)
+			if err != nil && err != solver.ErrAttemptLimit {
+				return err
+			}
+		}
+
+		runtime.ReadMemStats(&after)
+
+		perSolve := total / time.Duration(*runs)
+		speedup := ""
+		if i == 0 {
+			baseline = perSolve
+		} else if perSolve > 0 {
+			speedup = fmt.Sprintf("%.1fx", float64(baseline)/float64(perSolve))
+		}
+		solved := messages.T("cli.no")
+		if result.Success {
+			solved = messages.T("cli.yes")
+		}
+		fmt.Printf("%-8s %10d %8s %14v %9s %13d %13d %7d\n",
+			mode.name, result.AttemptCount, solved, perSolve.Round(time.Microsecond), speedup,
+			(after.Mallocs-before.Mallocs)/uint64(*runs),
+			(after.TotalAlloc-before.TotalAlloc)/uint64(*runs),
+			after.NumGC-before.NumGC)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Package cli implements the the_knight command line tool.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"the_knight/internal/i18n"
+	"the_knight/internal/web"
+)
+
+// messages translates the output of the commands; Run sets its language.
+var messages = i18n.New(i18n.Default)
+
+// command is a single CLI subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists the subcommands in the order they are shown in the usage text.
+func commands() []command {
+	return []command{
+		{"serve", messages.T("cli.cmdServe"), runServe},
+		{"solve", messages.T("cli.cmdSolve"), runSolve},
+		{"record", messages.T("cli.cmdRecord"), runRecord},
+		{"replay", messages.T("cli.cmdReplay"), runReplay},
+		{"heat", messages.T("cli.cmdHeat"), runHeat},
+		{"bench", messages.T("cli.cmdBench"), runBench},
+	}
+}
+
+// Run executes the CLI with the given arguments (without the program name)
+// and returns the process exit code. A leading --lang flag (or the locale
+// environment) selects the language of the messages.
+func Run(args []string) int {
+	lang, args := langArg(args)
+	messages = i18n.New(lang)
+
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(os.Stdout)
+		return 0
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+		if err := cmd.run(args[1:]); err != nil {
+			if err != flag.ErrHelp {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+			}
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n\n", messages.T("cli.unknownCommand", args[0]))
+	usage(os.Stderr)
+	return 2
+}
+
+// langArg strips a leading -lang/--lang flag from args and returns its value,
+// falling back to the POSIX locale variables.
+func langArg(args []string) (string, []string) {
+	if len(args) > 0 {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[0], "-"), "=")
+		if strings.HasPrefix(args[0], "-") && name == "lang" {
+			if hasValue {
+				return value, args[1:]
+			}
+			if len(args) > 1 {
+				return args[1], args[2:]
+			}
+			return "", args[1:]
+		}
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			return v, args
+		}
+	}
+	return "", args
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, messages.T("cli.usage"))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, messages.T("cli.usageCommands"))
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, messages.T("cli.usageFlags"))
+	fmt.Fprintln(w, messages.T("cli.usageLang", strings.Join(i18n.Supported, ", ")))
+}
+
+// newFlagSet returns the flag set of a command, with a translated usage
+// line; operands describes the arguments after the flags, e.g. " file.ktr".
+func newFlagSet(name, operands string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), messages.T("cli.usageCommand", name, operands))
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// runServe starts the web server, as the legacy entry point always did.
+func runServe(args []string) error {
+	// The environment sets the defaults, the same as for cmd/server
+	cfg, err := web.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	fs := newFlagSet("serve", "")
+	fs.StringVar(&cfg.Addr, "addr", cfg.Addr, messages.T("cli.flagAddr"))
+	fs.StringVar(&cfg.DataDir, "data-dir", cfg.DataDir, messages.T("cli.flagDataDir"))
+	fs.BoolVar(&cfg.Kiosk, "kiosk", cfg.Kiosk, messages.T("cli.flagKiosk"))
+	workers := fs.String("workers", strings.Join(cfg.Workers, ","), messages.T("cli.flagWorkers"))
+	fs.BoolVar(&cfg.Worker, "worker", cfg.Worker, messages.T("cli.flagWorker"))
+	fs.StringVar(&cfg.WorkerToken, "worker-token", cfg.WorkerToken, messages.T("cli.flagWorkerToken"))
+	fs.IntVar(&cfg.WorkerSlots, "worker-slots", cfg.WorkerSlots, messages.T("cli.flagWorkerSlots"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg.Workers = nil
+	if *workers != "" {
+		cfg.Workers = strings.Split(*workers, ",")
+	}
+
+	server := web.NewServer()
+	if err := server.Configure(cfg); err != nil {
+		return err
+	}
+
+	fmt.Println(messages.T("cli.serveStarting"))
+	fmt.Println(messages.T("cli.serveVisit", cfg.Addr))
+	if err := server.Start(cfg.Addr); err != nil {
+		return messages.Errorf("cli.serveFailed", err)
+	}
+	return nil
+}
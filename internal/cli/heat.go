@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"the_knight/internal/analysis"
+)
+
+// runHeat prints which start squares admit open and closed tours.
+func runHeat(args []string) error {
+	fs := newFlagSet("heat", "")
+	size := fs.Int("size", 8, messages.T("cli.flagBoardSize"))
+	budget := fs.Int("budget", analysis.DefaultHeatBudget, messages.T("cli.flagHeatBudget"))
+	svg := fs.String("svg", "", messages.T("cli.flagSVG"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := analysis.Heat(context.Background(), *size, *budget)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n\n", messages.T("cli.heatSummary",
+		report.Size, report.Size, report.OpenCount, report.ClosedCount, report.Unknown))
+	fmt.Print(report.Grid())
+	fmt.Printf("\n%s\n", messages.T("cli.heatLegend"))
+
+	if *svg != "" {
+		if err := os.WriteFile(*svg, report.SVG(), 0o644); err != nil {
+			return err
+		}
+		fmt.Println(messages.T("cli.heatWritten", *svg))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"the_knight/internal/recording"
+	"the_knight/internal/solver"
+)
+
+// runRecord solves a board and writes the complete run to a .ktr file.
+func runRecord(args []string) error {
+	fs := newFlagSet("record", "")
+	sf := addSolveFlags(fs)
+	tree := fs.String("tree", "", messages.T("cli.flagTree"))
+	treeCap := fs.Int("tree-cap", solver.DefaultTreeNodeCap, messages.T("cli.flagTreeCap"))
+	out := fs.String("o", "run"+recording.FileExtension, messages.T("cli.flagOut"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts, b, start, err := sf.options()
+	if err != nil {
+		return err
+	}
+	opts.RecordTree = *tree != ""
+	opts.TreeNodeCap = *treeCap
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, cancel := sf.context()
+	defer cancel()
+
+	result, err := recording.Record(ctx, f, *sf.size, start, opts)
+	if err != nil && err != solver.ErrAttemptLimit && err != context.DeadlineExceeded && !errors.Is(err, solver.ErrMemoryBudget) {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// A board over the memory budget is rejected before any tree is recorded
+	if *tree != "" && result.Tree != nil {
+		if err := writeTree(*tree, result.Tree); err != nil {
+			return err
+		}
+	}
+
+	outcome := "cli.outcomeNone"
+	switch {
+	case result.Success:
+		outcome = "cli.outcomeFound"
+	case err == solver.ErrAttemptLimit:
+		outcome = "cli.outcomeLimit"
+	case err == context.DeadlineExceeded:
+		outcome = "cli.outcomeTimeout"
+	case errors.Is(err, solver.ErrMemoryBudget):
+		outcome = "cli.outcomeMemory"
+	}
+	rows, cols := b.Dimensions()
+	fmt.Println(messages.T("cli.recorded", rows, cols, start.X, start.Y, *out, messages.T(outcome), result.AttemptCount))
+	return nil
+}
+
+// writeTree exports a search tree, choosing the format from the file extension.
+func writeTree(path string, tree *solver.SearchTree) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if filepath.Ext(path) == ".json" {
+		err = json.NewEncoder(f).Encode(tree)
+	} else {
+		err = tree.WriteDOT(f)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"the_knight/internal/recording"
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// ANSI escape sequences used by the terminal replay.
+const (
+	ansiClear   = "\x1b[H\x1b[2J"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiDim     = "\x1b[2m"
+	ansiReset   = "\x1b[0m"
+)
+
+// runReplay plays back a .ktr recording, either as a plain update log or as
+// an animated board in the terminal.
+func runReplay(args []string) error {
+	fs := newFlagSet("replay", " file"+recording.FileExtension)
+	tui := fs.Bool("tui", false, messages.T("cli.flagTUI"))
+	delay := fs.Duration("delay", 50*time.Millisecond, messages.T("cli.flagDelay"))
+	verify := fs.Bool("verify", false, messages.T("cli.flagVerify"))
+
+	// Allow flags after the file name: the_knight replay run.ktr --tui
+	var file string
+	for len(args) > 0 {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		if len(args) > 0 {
+			if file != "" {
+				return messages.Errorf("cli.oneRecording")
+			}
+			file, args = args[0], args[1:]
+		}
+	}
+	if file == "" {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rec, err := recording.Read(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	if *verify {
+		if err := rec.Verify(context.Background()); err != nil {
+			return messages.Errorf("cli.verifyFailed", err)
+		}
+		fmt.Println(messages.T("cli.verified"))
+	}
+
+	if *tui {
+		replayTUI(os.Stdout, rec, *delay)
+	} else {
+		replayLog(os.Stdout, rec)
+	}
+	return nil
+}
+
+// replayLog prints one line per update followed by the result.
+func replayLog(w io.Writer, rec *recording.Recording) {
+	for i, update := range rec.Updates {
+		switch {
+		case update.IsDeadEnd:
+			fmt.Fprintf(w, "%6d  %s\n", i+1, messages.T("cli.replayDeadEnd", update.Position.X, update.Position.Y, update.MoveNumber))
+		case update.IsBacktrack:
+			fmt.Fprintf(w, "%6d  %s\n", i+1, messages.T("cli.replayBacktrack", update.Position.X, update.Position.Y))
+		default:
+			fmt.Fprintf(w, "%6d  %s\n", i+1, messages.T("cli.replayMove", update.MoveNumber, update.Position.X, update.Position.Y))
+		}
+	}
+	fmt.Fprintln(w, summaryLine(rec))
+}
+
+// replayTUI redraws the board after every update.
+func replayTUI(w io.Writer, rec *recording.Recording, delay time.Duration) {
+	// Read has validated the board, so this cannot fail
+	cells, _ := rec.Header.Options.Board(rec.Header.Size)
+	rows, cols := cells.Dimensions()
+	boardName := messages.T("cli.replayBoard", rows, cols)
+	if len(rec.Header.Options.Shape) > 0 {
+		boardName = messages.T("cli.replayComposite", cells.SquareCount())
+	}
+
+	width := len(fmt.Sprint(rows * cols))
+	backtracks, deadEnds := 0, 0
+	for i, update := range rec.Updates {
+		pos := update.Position
+		switch {
+		case update.IsDeadEnd:
+			deadEnds++
+		case update.IsBacktrack:
+			cells[pos.X][pos.Y] = 0
+			backtracks++
+		default:
+			cells[pos.X][pos.Y] = update.MoveNumber
+		}
+
+		var sb strings.Builder
+		sb.WriteString(ansiClear)
+		fmt.Fprintf(&sb, "%s\n\n", messages.T("cli.replayHeader",
+			boardName, rec.Header.StartPos.X, rec.Header.StartPos.Y, algorithmName(rec.Header.Options), rec.Header.Options.Seed))
+		for x := 0; x < rows; x++ {
+			for y := 0; y < cols; y++ {
+				cell := fmt.Sprintf(" %*d ", width, cells[x][y])
+				switch {
+				case cells[x][y] == board.Blocked:
+					cell = fmt.Sprintf(" %*s ", width, "")
+				case x == pos.X && y == pos.Y && update.IsDeadEnd:
+					cell = ansiRed + ansiReverse + cell + ansiReset
+				case x == pos.X && y == pos.Y && update.IsBacktrack:
+					cell = ansiRed + fmt.Sprintf(" %*s ", width, "x") + ansiReset
+				case x == pos.X && y == pos.Y:
+					cell = ansiReverse + cell + ansiReset
+				case cells[x][y] == 0:
+					cell = ansiDim + fmt.Sprintf(" %*s ", width, ".") + ansiReset
+				}
+				sb.WriteString(cell)
+			}
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "\n%s\n", messages.T("cli.replayProgress", i+1, len(rec.Updates), backtracks, deadEnds))
+		io.WriteString(w, sb.String())
+
+		time.Sleep(delay)
+	}
+	fmt.Fprintln(w, summaryLine(rec))
+}
+
+// summaryLine describes the outcome of a recorded run.
+func summaryLine(rec *recording.Recording) string {
+	switch {
+	case rec.Summary.Success:
+		return messages.T("cli.found", rec.Summary.AttemptCount)
+	case rec.Summary.Error != "":
+		return messages.T("cli.runStopped", rec.Summary.AttemptCount, rec.Summary.Error)
+	default:
+		return messages.T("cli.noTourDeadEnds", rec.Summary.AttemptCount, rec.Summary.DeadEnds)
+	}
+}
+
+func algorithmName(opts solver.SolveOptions) string {
+	if opts.Algorithm == "" {
+		return solver.AlgorithmWarnsdorff
+	}
+	return opts.Algorithm
+}
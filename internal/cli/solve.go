@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// Output modes of the solve command.
+const (
+	streamNone   = ""
+	streamNDJSON = "ndjson"
+)
+
+// solveFlags are the board and search flags shared by the commands that run the solver.
+type solveFlags struct {
+	size        *int
+	shape       *string
+	x, y        *int
+	algorithm   *string
+	closed      *bool
+	seed        *int64
+	maxAttempts *int
+	timeout     *time.Duration
+	memoryMB    *int64
+}
+
+func addSolveFlags(fs *flag.FlagSet) *solveFlags {
+	return &solveFlags{
+		size:        fs.Int("size", 8, messages.T("cli.flagSize")),
+		shape:       fs.String("shape", "", messages.T("cli.flagShape")),
+		x:           fs.Int("x", 0, messages.T("cli.flagX")),
+		y:           fs.Int("y", 0, messages.T("cli.flagY")),
+		algorithm:   fs.String("algorithm", solver.AlgorithmWarnsdorff, messages.T("cli.flagAlgorithm")),
+		closed:      fs.Bool("closed", false, messages.T("cli.flagClosed")),
+		seed:        fs.Int64("seed", 0, messages.T("cli.flagSeed")),
+		maxAttempts: fs.Int("max-attempts", 0, messages.T("cli.flagMaxAttempts")),
+		timeout:     fs.Duration("timeout", 0, messages.T("cli.flagTimeout")),
+		memoryMB:    fs.Int64("memory-budget", 0, messages.T("cli.flagMemoryBudget")),
+	}
+}
+
+// options validates the parsed flags and returns the solve options, the
+// (empty) board they describe and the start square.
+func (f *solveFlags) options() (solver.SolveOptions, board.Board, board.Position, error) {
+	opts := solver.SolveOptions{
+		Algorithm:    *f.algorithm,
+		Closed:       *f.closed,
+		Seed:         *f.seed,
+		MaxAttempts:  *f.maxAttempts,
+		MemoryBudget: *f.memoryMB << 20,
+	}
+	start := board.Position{X: *f.x, Y: *f.y}
+	if *f.memoryMB < 0 {
+		return opts, nil, start, messages.Errorf("cli.negativeBudget", *f.memoryMB)
+	}
+	if !solver.IsValidAlgorithm(opts.Algorithm) {
+		return opts, nil, start, messages.Errorf("cli.unknownAlgorithm", opts.Algorithm)
+	}
+	if *f.shape != "" {
+		rects, err := board.ShapeRects(*f.shape, *f.size)
+		if err != nil {
+			return opts, nil, start, err
+		}
+		opts.Shape = rects
+	}
+
+	b, err := opts.Board(*f.size)
+	if err != nil {
+		return opts, nil, start, err
+	}
+	if !b.Contains(start) {
+		return opts, nil, start, messages.Errorf("cli.offBoard", start.X, start.Y)
+	}
+	return opts, b, start, nil
+}
+
+// context returns the context a solve runs under, bounded by -timeout.
+func (f *solveFlags) context() (context.Context, context.CancelFunc) {
+	if *f.timeout > 0 {
+		return context.WithTimeout(context.Background(), *f.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// runSolve solves a board and prints the tour, or streams every update as it happens.
+func runSolve(args []string) error {
+	fs := newFlagSet("solve", "")
+	sf := addSolveFlags(fs)
+	stream := fs.String("stream", streamNone, messages.T("cli.flagStream"))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *stream != streamNone && *stream != streamNDJSON {
+		return messages.Errorf("cli.unknownStream", *stream, streamNDJSON)
+	}
+
+	opts, b, start, err := sf.options()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := sf.context()
+	defer cancel()
+
+	// In streaming mode stdout carries nothing but updates, one JSON object
+	// per line, so it can be piped straight into jq; the summary goes to stderr.
+	summary := io.Writer(os.Stdout)
+	var writeErr error
+	if *stream == streamNDJSON {
+		summary = os.Stderr
+		enc := json.NewEncoder(os.Stdout)
+		opts.OnMove = func(update solver.MoveUpdate) {
+			if writeErr != nil {
+				return
+			}
+			// A closed pipe (e.g. | head) stops the search
+			if writeErr = enc.Encode(update); writeErr != nil {
+				cancel()
+			}
+		}
+	}
+
+	result, err := solver.NewSolver().SolveWithOptions(ctx, *sf.size, start, opts)
+	if writeErr != nil {
+		return writeErr
+	}
+
+	switch {
+	case err == nil && result.Success:
+		if *stream == streamNone {
+			printTour(summary, b, result.Moves)
+		}
+		fmt.Fprintln(summary, messages.T("cli.found", result.AttemptCount))
+	case err == nil:
+		fmt.Fprintln(summary, messages.T("cli.noTour", result.AttemptCount))
+	case err == solver.ErrAttemptLimit:
+		fmt.Fprintln(summary, messages.T("cli.attemptLimit", result.AttemptCount))
+	case err == context.DeadlineExceeded:
+		fmt.Fprintln(summary, messages.T("cli.timeout", result.AttemptCount))
+	case errors.Is(err, solver.ErrMemoryBudget):
+		fmt.Fprintln(summary, messages.T("cli.stopped", result.AttemptCount, err))
+	default:
+		return err
+	}
+	return nil
+}
+
+// printTour draws the board with the move number of every square.
+func printTour(w io.Writer, b board.Board, moves []solver.MoveUpdate) {
+	for _, move := range moves {
+		b.WriteToBoard(move.Position, move.MoveNumber)
+	}
+	rows, cols := b.Dimensions()
+	width := len(fmt.Sprint(rows * cols))
+
+	var sb strings.Builder
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			if b[x][y] == board.Blocked {
+				fmt.Fprintf(&sb, " %*s", width, "")
+			} else {
+				fmt.Fprintf(&sb, " %*d", width, b[x][y])
+			}
+		}
+		sb.WriteString("\n")
+	}
+	io.WriteString(w, sb.String())
+}
@@ -0,0 +1,246 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// ErrNoWorkers is returned when no worker is configured or every worker
+// became unreachable before the subtrees were searched.
+var ErrNoWorkers = errors.New("distributed: no workers available")
+
+// The split aims for subtreesPerWorker subtrees per worker, so a worker that
+// exhausts an easy subtree has more to take, going at most maxSplitDepth
+// moves deep.
+const (
+	subtreesPerWorker = 4
+	maxSplitDepth     = 6
+)
+
+// busyBackoff is how long a coordinator waits before offering a subtree
+// again to a worker whose slots were all busy.
+const busyBackoff = 500 * time.Millisecond
+
+// Coordinator splits solves across worker instances. A worker URL may be
+// listed more than once to give it more than one subtree at a time.
+type Coordinator struct {
+	workers []string
+	token   string
+	client  *http.Client
+}
+
+// NewCoordinator returns a coordinator for the workers at the given base
+// URLs, e.g. "http://10.0.0.2:8080", that authenticates with token.
+func NewCoordinator(workers []string, token string) (*Coordinator, error) {
+	c := &Coordinator{token: token, client: &http.Client{}}
+	for _, w := range workers {
+		u, err := url.Parse(strings.TrimSpace(w))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("distributed: invalid worker URL %q", w)
+		}
+		c.workers = append(c.workers, strings.TrimSuffix(u.String(), "/"))
+	}
+	if len(c.workers) == 0 {
+		return nil, ErrNoWorkers
+	}
+	return c, nil
+}
+
+// Workers returns the base URLs of the workers.
+func (c *Coordinator) Workers() []string {
+	return c.workers
+}
+
+// unavailableError marks a worker that cannot take tasks: unreachable, not
+// in worker mode, a kiosk, or expecting another token. Its subtree goes to
+// another worker.
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string { return e.err.Error() }
+
+// errBusy is returned by send when every slot of the worker was taken.
+var errBusy = errors.New("all worker slots are busy")
+
+// Solve runs a solve on the workers, with the signature of
+// solver.Solver.SolveWithOptions. The subtrees are handed out in the order
+// the search would try them, and the first tour any worker finds wins: the
+// other workers are cancelled. opts applies to every subtree, MaxAttempts
+// and MemoryBudget included. The result carries the tour without the
+// search's backtracking, and counts the attempts of the subtrees that
+// finished. Progress goes to opts.OnLog.
+func (c *Coordinator) Solve(ctx context.Context, size int, start board.Position, opts solver.SolveOptions) (*solver.SolveResult, error) {
+	if c == nil {
+		return nil, ErrNoWorkers
+	}
+	logf := func(format string, args ...any) {
+		if opts.OnLog != nil {
+			opts.OnLog(solver.LogInfo, fmt.Sprintf(format, args...))
+		}
+	}
+
+	var prefixes [][]board.Position
+	for depth := 1; depth <= maxSplitDepth && len(prefixes) < subtreesPerWorker*len(c.workers); depth++ {
+		var err error
+		if prefixes, err = solver.Prefixes(size, start, opts, depth); err != nil {
+			return nil, err
+		}
+	}
+	logf("Split into %d subtrees for %d workers", len(prefixes), len(c.workers))
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		next    = make([]int, len(prefixes)) // subtrees still to search, in order
+		alive   = len(c.workers)
+		result  = &solver.SolveResult{}
+		limited bool
+		failure error
+		wg      sync.WaitGroup
+	)
+	for i := range next {
+		next[i] = i
+	}
+
+	for _, worker := range c.workers {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				if len(next) == 0 || ctx.Err() != nil {
+					mu.Unlock()
+					return
+				}
+				i := next[0]
+				next = next[1:]
+				mu.Unlock()
+
+				task := Task{Size: size, StartPos: start, Options: opts}
+				task.Options.Prefix = prefixes[i]
+				out, err := c.send(ctx, worker, task)
+
+				mu.Lock()
+				var unavailable *unavailableError
+				switch {
+				case ctx.Err() != nil:
+					// Another worker found a tour, failed, or the solve was cancelled
+				case err == errBusy:
+					next = append([]int{i}, next...)
+				case errors.As(err, &unavailable):
+					next = append([]int{i}, next...)
+					alive--
+					logf("Worker %s unavailable, subtree %d re-queued: %v", worker, i+1, err)
+					if alive == 0 {
+						failure = ErrNoWorkers
+						cancel()
+					}
+				case err != nil:
+					failure = fmt.Errorf("distributed: worker %s: %w", worker, err)
+					cancel()
+				case out.MemoryBudget != nil:
+					failure = out.MemoryBudget
+					result.MemoryBytes = out.MemoryBudget.Used
+					cancel()
+				case out.Error != "":
+					failure = fmt.Errorf("distributed: worker %s: %s", worker, out.Error)
+					cancel()
+				default:
+					result.AttemptCount += out.Attempts
+					result.DeadEnds += out.DeadEnds
+					limited = limited || out.AttemptLimit
+					if out.Success {
+						result.Success = true
+						result.Moves = make([]solver.MoveUpdate, len(out.Path))
+						for n, pos := range out.Path {
+							result.Moves[n] = solver.MoveUpdate{Position: pos, MoveNumber: n + 1}
+						}
+						logf("Tour found by worker %s in subtree %d of %d", worker, i+1, len(prefixes))
+						cancel()
+					}
+				}
+				stop := (err != nil && err != errBusy) || ctx.Err() != nil
+				mu.Unlock()
+				if stop {
+					return
+				}
+				if err == errBusy {
+					select {
+					case <-time.After(busyBackoff):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	switch {
+	case result.Success:
+		return result, nil
+	case failure != nil:
+		logf("Stopped: %v", failure)
+		return result, failure
+	case parent.Err() != nil:
+		return result, parent.Err()
+	case limited:
+		return result, solver.ErrAttemptLimit
+	}
+	logf("All %d subtrees exhausted after %d attempts: no tour from this square", len(prefixes), result.AttemptCount)
+	return result, nil
+}
+
+// send runs a task on a worker.
+func (c *Coordinator) send(ctx context.Context, worker string, task Task) (Outcome, error) {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return Outcome{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, worker+WorkerPath, bytes.NewReader(body))
+	if err != nil {
+		return Outcome{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Outcome{}, &unavailableError{err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			return Outcome{}, errBusy
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+		// A bad request would be rejected by every worker
+		if resp.StatusCode == http.StatusBadRequest {
+			return Outcome{}, err
+		}
+		return Outcome{}, &unavailableError{err}
+	}
+	var out Outcome
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Outcome{}, &unavailableError{err}
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+// Package distributed spreads a solve over several server instances: a
+// coordinator splits the top of the search tree into subtrees (see
+// solver.Prefixes) and hands them to workers over HTTP, one at a time per
+// worker, until one of them finds a tour. Servers started in worker mode
+// answer Tasks at WorkerPath, authenticated with a token shared with the
+// coordinator (see Authorize).
+package distributed
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// WorkerPath is the endpoint a worker takes Tasks on.
+const WorkerPath = "/api/worker/solve"
+
+// Authorize reports whether a request to WorkerPath carries the shared
+// token as "Authorization: Bearer <token>".
+func Authorize(r *http.Request, token string) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// Task is one subtree of a distributed solve: the solve of a Size board from
+// StartPos below Options.Prefix.
+type Task struct {
+	Size     int                 `json:"size"`
+	StartPos board.Position      `json:"startPos"`
+	Options  solver.SolveOptions `json:"options"`
+}
+
+// Outcome is a worker's answer to a Task.
+type Outcome struct {
+	Success bool `json:"success"`
+	// Path is the tour, start square first, when Success is set
+	Path     []board.Position `json:"path,omitempty"`
+	Attempts int              `json:"attempts"`
+	DeadEnds int              `json:"deadEnds"`
+	// AttemptLimit is set when the subtree ran into Options.MaxAttempts
+	// before it was exhausted
+	AttemptLimit bool `json:"attemptLimit,omitempty"`
+	// MemoryBudget is set when the subtree exceeded Options.MemoryBudget
+	MemoryBudget *solver.MemoryBudgetError `json:"memoryBudget,omitempty"`
+	// Error is set when the search failed for another reason
+	Error string `json:"error,omitempty"`
+}
+
+// Work searches the subtree of a Task. It only returns an error when ctx
+// ends the search; the coordinator has given up on the task by then.
+func Work(ctx context.Context, task Task) (Outcome, error) {
+	opts := task.Options
+	opts.StreamMoves, opts.RecordTree = false, false
+
+	result, err := solver.NewSolver().SolveWithOptions(ctx, task.Size, task.StartPos, opts)
+	switch {
+	case err == context.Canceled || err == context.DeadlineExceeded:
+		return Outcome{}, err
+	case result == nil:
+		return Outcome{Error: err.Error()}, nil
+	}
+
+	out := Outcome{Success: result.Success, Attempts: result.AttemptCount, DeadEnds: result.DeadEnds}
+	switch {
+	case err == solver.ErrAttemptLimit:
+		out.AttemptLimit = true
+	case errors.As(err, &out.MemoryBudget):
+	case err != nil:
+		out.Error = err.Error()
+	}
+	if result.Success {
+		out.Path = make([]board.Position, len(result.Moves))
+		for i, move := range result.Moves {
+			out.Path[i] = move.Position
+		}
+	}
+	return out, nil
+}
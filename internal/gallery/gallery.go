@@ -0,0 +1,48 @@
+// Package gallery embeds a library of precomputed runs, so a server can show
+// finished tours without solving anything.
+package gallery
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"the_knight/internal/recording"
+)
+
+//go:embed tours/*.ktr
+var tours embed.FS
+
+// Tour is a run of the library.
+type Tour struct {
+	// Name is the file name of the run without its extension, e.g. "8x8-closed"
+	Name      string
+	Recording *recording.Recording
+}
+
+// Load parses every run of the library, in file name order.
+func Load() ([]Tour, error) {
+	files, err := fs.Glob(tours, "tours/*"+recording.FileExtension)
+	if err != nil {
+		return nil, err
+	}
+	library := make([]Tour, 0, len(files))
+	for _, file := range files {
+		f, err := tours.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := recording.Read(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("gallery tour %s: %w", file, err)
+		}
+		library = append(library, Tour{
+			Name:      strings.TrimSuffix(path.Base(file), recording.FileExtension),
+			Recording: rec,
+		})
+	}
+	return library, nil
+}
@@ -0,0 +1,23 @@
+package gallery
+
+import (
+	"context"
+	"testing"
+
+	"the_knight/internal/recording"
+)
+
+func TestLibraryVerifies(t *testing.T) {
+	library, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tour := range library {
+		if v := tour.Recording.Header.Version; v != recording.FormatVersion {
+			t.Errorf("%s: format version %d, want %d", tour.Name, v, recording.FormatVersion)
+		}
+		if err := tour.Recording.Verify(context.Background()); err != nil {
+			t.Errorf("%s: %v", tour.Name, err)
+		}
+	}
+}
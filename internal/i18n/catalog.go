@@ -0,0 +1,328 @@
+package i18n
+
+// catalogs maps languages to their messages. Keys are grouped by where the
+// message appears: "page." for the web pages (formatted in the browser, so
+// only %s and %d), "cli." for the command line. English must have every key.
+var catalogs = map[string]map[string]string{
+	"en": {
+		"page.title":          "Knight's Tour Solver",
+		"page.solve":          "Start Solve",
+		"page.reset":          "Reset",
+		"page.startPosition":  "Starting Position:",
+		"page.clickHint":      "(Or click a cell to select)",
+		"page.ready":          "Ready to solve",
+		"page.solving":        "Solving...",
+		"page.found":          "Solution found! Rendering...",
+		"page.complete":       "Solution complete!",
+		"page.noSolution":     "No solution found",
+		"page.timeout":        "Search timed out",
+		"page.connection":     "Connection error",
+		"page.solverError":    "Solver error: %s",
+		"page.stats":          "Attempts: %s · Dead ends: %s",
+		"page.galleryTitle":   "Knight's Tour Gallery",
+		"page.gallerySubtext": "Precomputed tours, replayed move by move. Click a board to replay it.",
+		"page.galleryDetails": "%s, %s attempts",
+		"page.closed":         "closed",
+
+		"cli.unknownCommand":   "unknown command %q",
+		"cli.unknownAlgorithm": "unknown algorithm %q",
+		"cli.offBoard":         "start position (%d, %d) is off the board",
+		"cli.negativeBudget":   "negative memory budget %d",
+		"cli.found":            "Tour found after %d attempts",
+		"cli.noTour":           "No tour found after %d attempts",
+		"cli.noTourDeadEnds":   "No tour found after %d attempts (%d dead ends)",
+		"cli.attemptLimit":     "Gave up at the attempt limit after %d attempts",
+		"cli.timeout":          "Timed out after %d attempts",
+		"cli.stopped":          "Stopped after %d attempts: %v",
+		"cli.runStopped":       "Run stopped after %d attempts: %s",
+		"cli.recorded":         "Recorded %dx%d run from (%d, %d) to %s: %s after %d attempts",
+		"cli.outcomeFound":     "tour found",
+		"cli.outcomeNone":      "no tour found",
+		"cli.outcomeLimit":     "gave up at the attempt limit",
+		"cli.outcomeTimeout":   "timed out",
+		"cli.outcomeMemory":    "ran out of memory budget",
+		"cli.verified":         "Recording verified: the solver reproduces it exactly",
+		"cli.verifyFailed":     "verification failed: %w",
+		"cli.replayMove":       "move %d -> (%d, %d)",
+		"cli.replayBacktrack":  "backtrack (%d, %d)",
+		"cli.replayDeadEnd":    "dead end at (%d, %d), depth %d",
+		"cli.replayBoard":      "%dx%d board",
+		"cli.replayComposite":  "composite board (%d squares)",
+		"cli.replayHeader":     "%s, start (%d, %d), algorithm %s, seed %d",
+		"cli.replayProgress":   "update %d/%d, backtracks %d, dead ends %d",
+		"cli.heatSummary":      "%dx%d board: %d squares with open tours, %d with closed tours, %d unknown",
+		"cli.heatLegend":       "C = closed and open, O = open only, - = none, ? = unknown",
+		"cli.heatWritten":      "Heatmap written to %s",
+		"cli.benchHeader":      "%dx%d board from (%d, %d), %d runs per mode",
+		"cli.benchMode":        "mode",
+		"cli.benchAttempts":    "attempts",
+		"cli.benchSolved":      "solved",
+		"cli.benchTime":        "time/solve",
+		"cli.benchSpeedup":     "speedup",
+		"cli.benchAllocs":      "allocs/solve",
+		"cli.benchBytes":       "bytes/solve",
+		"cli.benchGCs":         "GCs",
+		"cli.yes":              "yes",
+		"cli.no":               "no",
+		"cli.usage":            "Usage: the_knight <command> [flags]",
+		"cli.usageCommand":     "Usage: the_knight %s [flags]%s",
+		"cli.usageCommands":    "Commands:",
+		"cli.usageFlags":       "Run 'the_knight <command> -h' for the flags of a command.",
+		"cli.usageLang":        "Put --lang (%s) before the command to translate its messages.",
+		"cli.cmdServe":         "start the web server (default)",
+		"cli.cmdSolve":         "solve a board and print the tour (or stream it as NDJSON)",
+		"cli.cmdRecord":        "solve and record the run to a .ktr file",
+		"cli.cmdReplay":        "replay a .ktr recording",
+		"cli.cmdHeat":          "report which start squares admit open/closed tours",
+		"cli.cmdBench":         "time the solver with and without the degree cache",
+		"cli.serveStarting":    "Starting Knight's Tour Web Server...",
+		"cli.serveVisit":       "Visit http://localhost%s in your browser",
+		"cli.serveFailed":      "server failed to start: %w",
+		"cli.unknownStream":    "unknown stream format %q (want %s)",
+		"cli.oneRecording":     "only one recording can be replayed at a time",
+		"cli.invalidSize":      "invalid board size %d",
+		"cli.invalidRuns":      "invalid number of runs %d",
+		"cli.flagSize":         "board size (block size with -shape)",
+		"cli.flagBoardSize":    "board size",
+		"cli.flagShape":        "composite board of size x size blocks (L, plus)",
+		"cli.flagX":            "start row",
+		"cli.flagY":            "start column",
+		"cli.flagAlgorithm":    "search algorithm (warnsdorff, backtracking)",
+		"cli.flagClosed":       "require a closed (re-entrant) tour",
+		"cli.flagSeed":         "tie-breaking seed (0 = fixed move order)",
+		"cli.flagMaxAttempts":  "give up after this many attempts (0 = no limit)",
+		"cli.flagTimeout":      "stop the search after this long (0 = no limit)",
+		"cli.flagMemoryBudget": "stop the search once it needs more than this many MB (0 = no limit)",
+		"cli.flagStream":       "write every move update to stdout as it happens (ndjson)",
+		"cli.flagTree":         "also export the search tree to this file (.dot or .json)",
+		"cli.flagTreeCap":      "maximum number of search tree nodes to keep",
+		"cli.flagOut":          "output file",
+		"cli.flagTUI":          "animate the run on a board in the terminal",
+		"cli.flagDelay":        "delay between frames in --tui mode",
+		"cli.flagVerify":       "re-run the solver and check the recording matches",
+		"cli.flagRuns":         "solves per mode",
+		"cli.flagHeatBudget":   "attempt budget per searched square",
+		"cli.flagSVG":          "also write an SVG heatmap to this file",
+		"cli.flagAddr":         "address to listen on (env PORT)",
+		"cli.flagDataDir":      "persist unfinished solves here and resume them after a restart",
+		"cli.flagKiosk":        "serve a read-only gallery of precomputed tours with solving disabled",
+		"cli.flagWorkers":      "comma-separated base URLs of worker instances for distributed solves",
+		"cli.flagWorker":       "run subtrees of distributed solves for coordinators",
+		"cli.flagWorkerToken":  "token shared by a coordinator and its workers",
+		"cli.flagWorkerSlots":  "subtrees a worker runs at a time (env WORKER_SLOTS)",
+	},
+	"es": {
+		"page.title":          "Solucionador del recorrido del caballo",
+		"page.solve":          "Resolver",
+		"page.reset":          "Reiniciar",
+		"page.startPosition":  "Casilla inicial:",
+		"page.clickHint":      "(O haz clic en una casilla)",
+		"page.ready":          "Listo para resolver",
+		"page.solving":        "Resolviendo...",
+		"page.found":          "¡Solución encontrada! Dibujando...",
+		"page.complete":       "¡Recorrido completo!",
+		"page.noSolution":     "No se encontró solución",
+		"page.timeout":        "Se agotó el tiempo de búsqueda",
+		"page.connection":     "Error de conexión",
+		"page.solverError":    "Error del solucionador: %s",
+		"page.stats":          "Intentos: %s · Callejones sin salida: %s",
+		"page.galleryTitle":   "Galería de recorridos del caballo",
+		"page.gallerySubtext": "Recorridos precalculados, reproducidos movimiento a movimiento. Haz clic en un tablero para repetirlo.",
+		"page.galleryDetails": "%s, %s intentos",
+		"page.closed":         "cerrado",
+
+		"cli.unknownCommand":   "comando desconocido %q",
+		"cli.unknownAlgorithm": "algoritmo desconocido %q",
+		"cli.offBoard":         "la casilla inicial (%d, %d) está fuera del tablero",
+		"cli.negativeBudget":   "presupuesto de memoria negativo %d",
+		"cli.found":            "Recorrido encontrado tras %d intentos",
+		"cli.noTour":           "No se encontró recorrido tras %d intentos",
+		"cli.noTourDeadEnds":   "No se encontró recorrido tras %d intentos (%d callejones sin salida)",
+		"cli.attemptLimit":     "Abandonado en el límite de intentos tras %d intentos",
+		"cli.timeout":          "Tiempo agotado tras %d intentos",
+		"cli.stopped":          "Detenido tras %d intentos: %v",
+		"cli.runStopped":       "Ejecución detenida tras %d intentos: %s",
+		"cli.recorded":         "Ejecución de %dx%d desde (%d, %d) grabada en %s: %s tras %d intentos",
+		"cli.outcomeFound":     "recorrido encontrado",
+		"cli.outcomeNone":      "ningún recorrido encontrado",
+		"cli.outcomeLimit":     "abandonado en el límite de intentos",
+		"cli.outcomeTimeout":   "tiempo agotado",
+		"cli.outcomeMemory":    "presupuesto de memoria agotado",
+		"cli.verified":         "Grabación verificada: el solucionador la reproduce exactamente",
+		"cli.verifyFailed":     "la verificación falló: %w",
+		"cli.replayMove":       "movimiento %d -> (%d, %d)",
+		"cli.replayBacktrack":  "retroceso (%d, %d)",
+		"cli.replayDeadEnd":    "callejón sin salida en (%d, %d), profundidad %d",
+		"cli.replayBoard":      "Tablero de %dx%d",
+		"cli.replayComposite":  "Tablero compuesto (%d casillas)",
+		"cli.replayHeader":     "%s, inicio (%d, %d), algoritmo %s, semilla %d",
+		"cli.replayProgress":   "paso %d/%d, retrocesos %d, callejones sin salida %d",
+		"cli.heatSummary":      "Tablero de %dx%d: %d casillas con recorridos abiertos, %d con recorridos cerrados, %d sin determinar",
+		"cli.heatLegend":       "C = cerrado y abierto, O = solo abierto, - = ninguno, ? = sin determinar",
+		"cli.heatWritten":      "Mapa de calor escrito en %s",
+		"cli.benchHeader":      "Tablero de %dx%d desde (%d, %d), %d ejecuciones por modo",
+		"cli.benchMode":        "modo",
+		"cli.benchAttempts":    "intentos",
+		"cli.benchSolved":      "resuelto",
+		"cli.benchTime":        "tiempo/ejec.",
+		"cli.benchSpeedup":     "mejora",
+		"cli.benchAllocs":      "allocs/ejec.",
+		"cli.benchBytes":       "bytes/ejec.",
+		"cli.benchGCs":         "GCs",
+		"cli.yes":              "sí",
+		"cli.no":               "no",
+		"cli.usage":            "Uso: the_knight <comando> [opciones]",
+		"cli.usageCommand":     "Uso: the_knight %s [opciones]%s",
+		"cli.usageCommands":    "Comandos:",
+		"cli.usageFlags":       "Ejecuta 'the_knight <comando> -h' para ver las opciones de un comando.",
+		"cli.usageLang":        "Pon --lang (%s) antes del comando para traducir sus mensajes.",
+		"cli.cmdServe":         "inicia el servidor web (predeterminado)",
+		"cli.cmdSolve":         "resuelve un tablero e imprime el recorrido (o lo emite como NDJSON)",
+		"cli.cmdRecord":        "resuelve y graba la ejecución en un archivo .ktr",
+		"cli.cmdReplay":        "reproduce una grabación .ktr",
+		"cli.cmdHeat":          "indica qué casillas iniciales admiten recorridos abiertos/cerrados",
+		"cli.cmdBench":         "mide el solucionador con y sin la caché de grados",
+		"cli.serveStarting":    "Iniciando el servidor web del recorrido del caballo...",
+		"cli.serveVisit":       "Abre http://localhost%s en tu navegador",
+		"cli.serveFailed":      "no se pudo iniciar el servidor: %w",
+		"cli.unknownStream":    "formato de flujo desconocido %q (se espera %s)",
+		"cli.oneRecording":     "solo se puede reproducir una grabación a la vez",
+		"cli.invalidSize":      "tamaño de tablero no válido %d",
+		"cli.invalidRuns":      "número de ejecuciones no válido %d",
+		"cli.flagSize":         "tamaño del tablero (tamaño de bloque con -shape)",
+		"cli.flagBoardSize":    "tamaño del tablero",
+		"cli.flagShape":        "tablero compuesto de bloques de size x size (L, plus)",
+		"cli.flagX":            "fila inicial",
+		"cli.flagY":            "columna inicial",
+		"cli.flagAlgorithm":    "algoritmo de búsqueda (warnsdorff, backtracking)",
+		"cli.flagClosed":       "exige un recorrido cerrado",
+		"cli.flagSeed":         "semilla de desempate (0 = orden de movimientos fijo)",
+		"cli.flagMaxAttempts":  "abandona tras tantos intentos (0 = sin límite)",
+		"cli.flagTimeout":      "detiene la búsqueda tras este tiempo (0 = sin límite)",
+		"cli.flagMemoryBudget": "detiene la búsqueda si necesita más de tantos MB (0 = sin límite)",
+		"cli.flagStream":       "escribe cada movimiento en stdout en cuanto ocurre (ndjson)",
+		"cli.flagTree":         "exporta también el árbol de búsqueda a este archivo (.dot o .json)",
+		"cli.flagTreeCap":      "número máximo de nodos del árbol de búsqueda",
+		"cli.flagOut":          "archivo de salida",
+		"cli.flagTUI":          "anima la ejecución en un tablero en la terminal",
+		"cli.flagDelay":        "pausa entre fotogramas en modo --tui",
+		"cli.flagVerify":       "vuelve a ejecutar el solucionador y comprueba que coincide con la grabación",
+		"cli.flagRuns":         "resoluciones por modo",
+		"cli.flagHeatBudget":   "presupuesto de intentos por casilla buscada",
+		"cli.flagSVG":          "escribe también un mapa de calor SVG en este archivo",
+		"cli.flagAddr":         "dirección de escucha (env PORT)",
+		"cli.flagDataDir":      "guarda aquí las resoluciones sin terminar y las reanuda tras un reinicio",
+		"cli.flagKiosk":        "sirve una galería de solo lectura de recorridos precalculados, sin resolver",
+		"cli.flagWorkers":      "URL base de los trabajadores para resoluciones distribuidas, separadas por comas",
+		"cli.flagWorker":       "ejecuta subárboles de resoluciones distribuidas para coordinadores",
+		"cli.flagWorkerToken":  "token compartido por un coordinador y sus trabajadores",
+		"cli.flagWorkerSlots":  "subárboles que un trabajador ejecuta a la vez (env WORKER_SLOTS)",
+	},
+	"de": {
+		"page.title":          "Springerproblem-Löser",
+		"page.solve":          "Lösen",
+		"page.reset":          "Zurücksetzen",
+		"page.startPosition":  "Startfeld:",
+		"page.clickHint":      "(Oder ein Feld anklicken)",
+		"page.ready":          "Bereit",
+		"page.solving":        "Suche läuft...",
+		"page.found":          "Lösung gefunden! Wird gezeichnet...",
+		"page.complete":       "Rundreise vollständig!",
+		"page.noSolution":     "Keine Lösung gefunden",
+		"page.timeout":        "Zeitlimit der Suche überschritten",
+		"page.connection":     "Verbindungsfehler",
+		"page.solverError":    "Fehler des Lösers: %s",
+		"page.stats":          "Versuche: %s · Sackgassen: %s",
+		"page.galleryTitle":   "Galerie der Springerrundreisen",
+		"page.gallerySubtext": "Vorberechnete Rundreisen, Zug für Zug abgespielt. Zum Wiederholen ein Brett anklicken.",
+		"page.galleryDetails": "%s, %s Versuche",
+		"page.closed":         "geschlossen",
+
+		"cli.unknownCommand":   "unbekannter Befehl %q",
+		"cli.unknownAlgorithm": "unbekannter Algorithmus %q",
+		"cli.offBoard":         "Startfeld (%d, %d) liegt außerhalb des Bretts",
+		"cli.negativeBudget":   "negatives Speicherbudget %d",
+		"cli.found":            "Rundreise nach %d Versuchen gefunden",
+		"cli.noTour":           "Keine Rundreise nach %d Versuchen gefunden",
+		"cli.noTourDeadEnds":   "Keine Rundreise nach %d Versuchen gefunden (%d Sackgassen)",
+		"cli.attemptLimit":     "Nach %d Versuchen am Versuchslimit aufgegeben",
+		"cli.timeout":          "Zeitlimit nach %d Versuchen überschritten",
+		"cli.stopped":          "Nach %d Versuchen abgebrochen: %v",
+		"cli.runStopped":       "Lauf nach %d Versuchen abgebrochen: %s",
+		"cli.recorded":         "%dx%d-Lauf ab (%d, %d) in %s aufgezeichnet: %s nach %d Versuchen",
+		"cli.outcomeFound":     "Rundreise gefunden",
+		"cli.outcomeNone":      "keine Rundreise gefunden",
+		"cli.outcomeLimit":     "am Versuchslimit aufgegeben",
+		"cli.outcomeTimeout":   "Zeitlimit überschritten",
+		"cli.outcomeMemory":    "Speicherbudget erschöpft",
+		"cli.verified":         "Aufzeichnung geprüft: der Löser reproduziert sie exakt",
+		"cli.verifyFailed":     "Prüfung fehlgeschlagen: %w",
+		"cli.replayMove":       "Zug %d -> (%d, %d)",
+		"cli.replayBacktrack":  "Rücknahme (%d, %d)",
+		"cli.replayDeadEnd":    "Sackgasse bei (%d, %d), Tiefe %d",
+		"cli.replayBoard":      "%dx%d-Brett",
+		"cli.replayComposite":  "Zusammengesetztes Brett (%d Felder)",
+		"cli.replayHeader":     "%s, Start (%d, %d), Algorithmus %s, Seed %d",
+		"cli.replayProgress":   "Schritt %d/%d, Rücknahmen %d, Sackgassen %d",
+		"cli.heatSummary":      "%dx%d-Brett: %d Felder mit offenen Rundreisen, %d mit geschlossenen, %d unbekannt",
+		"cli.heatLegend":       "C = geschlossen und offen, O = nur offen, - = keine, ? = unbekannt",
+		"cli.heatWritten":      "Heatmap nach %s geschrieben",
+		"cli.benchHeader":      "%dx%d-Brett ab (%d, %d), %d Läufe pro Modus",
+		"cli.benchMode":        "Modus",
+		"cli.benchAttempts":    "Versuche",
+		"cli.benchSolved":      "gelöst",
+		"cli.benchTime":        "Zeit/Lauf",
+		"cli.benchSpeedup":     "Faktor",
+		"cli.benchAllocs":      "Allocs/Lauf",
+		"cli.benchBytes":       "Bytes/Lauf",
+		"cli.benchGCs":         "GCs",
+		"cli.yes":              "ja",
+		"cli.no":               "nein",
+		"cli.usage":            "Aufruf: the_knight <Befehl> [Optionen]",
+		"cli.usageCommand":     "Aufruf: the_knight %s [Optionen]%s",
+		"cli.usageCommands":    "Befehle:",
+		"cli.usageFlags":       "'the_knight <Befehl> -h' zeigt die Optionen eines Befehls.",
+		"cli.usageLang":        "--lang (%s) vor dem Befehl übersetzt seine Meldungen.",
+		"cli.cmdServe":         "startet den Webserver (Standard)",
+		"cli.cmdSolve":         "löst ein Brett und gibt die Rundreise aus (oder streamt sie als NDJSON)",
+		"cli.cmdRecord":        "löst und zeichnet den Lauf in einer .ktr-Datei auf",
+		"cli.cmdReplay":        "spielt eine .ktr-Aufzeichnung ab",
+		"cli.cmdHeat":          "zeigt, von welchen Startfeldern offene/geschlossene Rundreisen ausgehen",
+		"cli.cmdBench":         "misst den Löser mit und ohne Grad-Cache",
+		"cli.serveStarting":    "Springerproblem-Webserver startet...",
+		"cli.serveVisit":       "http://localhost%s im Browser öffnen",
+		"cli.serveFailed":      "Server konnte nicht starten: %w",
+		"cli.unknownStream":    "unbekanntes Streamformat %q (erwartet %s)",
+		"cli.oneRecording":     "es kann nur eine Aufzeichnung auf einmal abgespielt werden",
+		"cli.invalidSize":      "ungültige Brettgröße %d",
+		"cli.invalidRuns":      "ungültige Anzahl von Läufen %d",
+		"cli.flagSize":         "Brettgröße (Blockgröße mit -shape)",
+		"cli.flagBoardSize":    "Brettgröße",
+		"cli.flagShape":        "zusammengesetztes Brett aus size x size Blöcken (L, plus)",
+		"cli.flagX":            "Startzeile",
+		"cli.flagY":            "Startspalte",
+		"cli.flagAlgorithm":    "Suchalgorithmus (warnsdorff, backtracking)",
+		"cli.flagClosed":       "verlangt eine geschlossene Rundreise",
+		"cli.flagSeed":         "Seed für Gleichstände (0 = feste Zugreihenfolge)",
+		"cli.flagMaxAttempts":  "nach so vielen Versuchen aufgeben (0 = kein Limit)",
+		"cli.flagTimeout":      "Suche nach dieser Dauer abbrechen (0 = kein Limit)",
+		"cli.flagMemoryBudget": "Suche abbrechen, sobald sie mehr als so viele MB braucht (0 = kein Limit)",
+		"cli.flagStream":       "jede Zugänderung sofort auf stdout schreiben (ndjson)",
+		"cli.flagTree":         "zusätzlich den Suchbaum in diese Datei exportieren (.dot oder .json)",
+		"cli.flagTreeCap":      "maximale Anzahl gespeicherter Suchbaumknoten",
+		"cli.flagOut":          "Ausgabedatei",
+		"cli.flagTUI":          "den Lauf auf einem Brett im Terminal animieren",
+		"cli.flagDelay":        "Pause zwischen Bildern im --tui-Modus",
+		"cli.flagVerify":       "den Löser erneut ausführen und die Aufzeichnung prüfen",
+		"cli.flagRuns":         "Läufe pro Modus",
+		"cli.flagHeatBudget":   "Versuchsbudget pro durchsuchtem Feld",
+		"cli.flagSVG":          "zusätzlich eine SVG-Heatmap in diese Datei schreiben",
+		"cli.flagAddr":         "Adresse, auf der gelauscht wird (env PORT)",
+		"cli.flagDataDir":      "unfertige Lösungen hier speichern und nach einem Neustart fortsetzen",
+		"cli.flagKiosk":        "eine schreibgeschützte Galerie vorberechneter Rundreisen ohne Lösen anbieten",
+		"cli.flagWorkers":      "kommagetrennte Basis-URLs der Worker für verteilte Lösungen",
+		"cli.flagWorker":       "Teilbäume verteilter Lösungen für Koordinatoren durchsuchen",
+		"cli.flagWorkerToken":  "Token, den ein Koordinator mit seinen Workern teilt",
+		"cli.flagWorkerSlots":  "Teilbäume, die ein Worker gleichzeitig durchsucht (env WORKER_SLOTS)",
+	},
+}
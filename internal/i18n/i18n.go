@@ -0,0 +1,114 @@
+// Package i18n translates the messages of the web pages and the command line.
+// Messages are looked up by key in per-language catalogs (see catalog.go) and
+// fall back to English when a catalog lacks a key.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the language used when no supported language was asked for.
+const Default = "en"
+
+// Supported lists the languages with a catalog.
+var Supported = []string{"en", "es", "de"}
+
+// Match returns the supported language of a language tag such as "de",
+// "es-MX" or the POSIX locale "de_DE.UTF-8", or "" if there is none.
+func Match(tag string) string {
+	base := strings.ToLower(tag)
+	if i := strings.IndexAny(base, "-_.@"); i >= 0 {
+		base = base[:i]
+	}
+	if _, ok := catalogs[base]; ok {
+		return base
+	}
+	return ""
+}
+
+// Negotiate picks the supported language the client prefers most from an
+// Accept-Language header, e.g. "de-CH, de;q=0.9, en;q=0.5".
+func Negotiate(acceptLanguage string) string {
+	type choice struct {
+		lang string
+		q    float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if lang := Match(tag); lang != "" && q > 0 {
+			choices = append(choices, choice{lang, q})
+		}
+	}
+	if len(choices) == 0 {
+		return Default
+	}
+	// Equal weights keep the order of the header
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	return choices[0].lang
+}
+
+// Printer formats the messages of one language.
+type Printer struct {
+	lang string
+}
+
+// New returns the printer of a language tag, or of Default if the tag is not supported.
+func New(tag string) Printer {
+	lang := Match(tag)
+	if lang == "" {
+		lang = Default
+	}
+	return Printer{lang: lang}
+}
+
+// Lang returns the language of the printer.
+func (p Printer) Lang() string {
+	return p.lang
+}
+
+// T returns the message of key formatted with args (fmt verbs), or the key
+// itself if no catalog has it.
+func (p Printer) T(key string, args ...any) string {
+	if len(args) == 0 {
+		return p.message(key)
+	}
+	return fmt.Sprintf(p.message(key), args...)
+}
+
+// Errorf is T for errors; like fmt.Errorf, a %w verb wraps its argument.
+func (p Printer) Errorf(key string, args ...any) error {
+	return fmt.Errorf(p.message(key), args...)
+}
+
+func (p Printer) message(key string) string {
+	if msg, ok := catalogs[p.lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[Default][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// PageMessages returns the unformatted "page." messages of the language by
+// key, for pages that format messages in the browser.
+func (p Printer) PageMessages() map[string]string {
+	messages := make(map[string]string)
+	for key := range catalogs[Default] {
+		if strings.HasPrefix(key, "page.") {
+			messages[key] = p.message(key)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,384 @@
+// Package recording implements the .ktr run-recording format.
+//
+// A .ktr file is JSON lines: one header line describing the run (board size,
+// start square, solver options including the seed), one line per MoveUpdate
+// in the order the solver emitted them (backtracks included) and a final
+// result line. Because the solver is deterministic for a given set of
+// options, a recording can both be replayed and re-verified.
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// FormatVersion is the current .ktr format version.
+const FormatVersion = 1
+
+// FileExtension is the conventional extension of recordings.
+const FileExtension = ".ktr"
+
+// Line types.
+const (
+	lineHeader = "header"
+	lineMove   = "move"
+	lineResult = "result"
+)
+
+// maxLineSize bounds a single line; header lines are the largest and stay well below this.
+const maxLineSize = 1 << 20
+
+// MaxBoardSide bounds the board of a recording, rows and columns alike, so a
+// header cannot make Read allocate a huge board. It matches the largest
+// board the server solves.
+const MaxBoardSide = 200
+
+// Header describes the recorded run.
+type Header struct {
+	Version   int                 `json:"version"`
+	Size      int                 `json:"size"`
+	StartPos  board.Position      `json:"startPos"`
+	Options   solver.SolveOptions `json:"options"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+// Summary is the outcome stored on the last line of a recording.
+type Summary struct {
+	Success      bool   `json:"success"`
+	AttemptCount int    `json:"attemptCount"`
+	DeadEnds     int    `json:"deadEnds,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Recording is a fully loaded .ktr file.
+type Recording struct {
+	Header  Header
+	Updates []solver.MoveUpdate
+	Summary Summary
+	// board and squares describe the empty board of the run, used to validate moves
+	board   board.Board
+	squares int
+}
+
+// line is the on-disk shape of every line; only the fields of its type are set.
+type line struct {
+	Type string `json:"type"`
+	*Header
+	*solver.MoveUpdate
+	*Summary
+}
+
+// Writer streams a recording to an io.Writer.
+type Writer struct {
+	enc *json.Encoder
+	err error
+}
+
+// NewWriter writes the header and returns a Writer for the moves.
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	header.Version = FormatVersion
+	if header.CreatedAt.IsZero() {
+		header.CreatedAt = time.Now().UTC()
+	}
+	rw := &Writer{enc: json.NewEncoder(w)}
+	if err := rw.enc.Encode(line{Type: lineHeader, Header: &header}); err != nil {
+		return nil, err
+	}
+	return rw, nil
+}
+
+// WriteMove appends a move update. After the first error all writes are
+// skipped and the error is reported by Close.
+func (rw *Writer) WriteMove(update solver.MoveUpdate) {
+	if rw.err != nil {
+		return
+	}
+	rw.err = rw.enc.Encode(line{Type: lineMove, MoveUpdate: &update})
+}
+
+// Close writes the result line.
+func (rw *Writer) Close(summary Summary) error {
+	if rw.err != nil {
+		return rw.err
+	}
+	return rw.enc.Encode(line{Type: lineResult, Summary: &summary})
+}
+
+// Write encodes a loaded recording back into the .ktr format.
+func (rec *Recording) Write(w io.Writer) error {
+	rw, err := NewWriter(w, rec.Header)
+	if err != nil {
+		return err
+	}
+	for _, update := range rec.Updates {
+		rw.WriteMove(update)
+	}
+	return rw.Close(rec.Summary)
+}
+
+// Record runs a solve and writes every update to w as it happens.
+func Record(ctx context.Context, w io.Writer, size int, startPos board.Position, opts solver.SolveOptions) (*solver.SolveResult, error) {
+	rw, err := NewWriter(w, Header{Size: size, StartPos: startPos, Options: opts})
+	if err != nil {
+		return nil, err
+	}
+
+	observer := opts.OnMove
+	opts.OnMove = func(update solver.MoveUpdate) {
+		rw.WriteMove(update)
+		if observer != nil {
+			observer(update)
+		}
+	}
+
+	result, solveErr := solver.NewSolver().SolveWithOptions(ctx, size, startPos, opts)
+	summary := Summary{}
+	if result != nil {
+		summary.Success = result.Success
+		summary.AttemptCount = result.AttemptCount
+		summary.DeadEnds = result.DeadEnds
+	}
+	if solveErr != nil {
+		summary.Error = solveErr.Error()
+	}
+	if err := rw.Close(summary); err != nil {
+		return result, err
+	}
+	return result, solveErr
+}
+
+// Read parses and validates a recording.
+func Read(r io.Reader) (*Recording, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	rec := &Recording{}
+	lineNo := 0
+	sawHeader, sawResult := false, false
+
+	for scanner.Scan() {
+		lineNo++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if sawResult {
+			return nil, fmt.Errorf("line %d: data after result line", lineNo)
+		}
+
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		if (l.Type == lineHeader && l.Header == nil) || (l.Type == lineMove && l.MoveUpdate == nil) || (l.Type == lineResult && l.Summary == nil) {
+			return nil, fmt.Errorf("line %d: %s line without data", lineNo, l.Type)
+		}
+
+		switch {
+		case !sawHeader && l.Type != lineHeader:
+			return nil, fmt.Errorf("line %d: expected header, got %q", lineNo, l.Type)
+		case l.Type == lineHeader:
+			if sawHeader {
+				return nil, fmt.Errorf("line %d: duplicate header", lineNo)
+			}
+			if l.Header.Version != FormatVersion {
+				return nil, fmt.Errorf("line %d: unsupported format version %d", lineNo, l.Header.Version)
+			}
+			if err := checkBounds(*l.Header); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			b, err := l.Header.Options.Board(l.Header.Size)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			rec.Header = *l.Header
+			rec.board, rec.squares = b, b.SquareCount()
+			sawHeader = true
+		case l.Type == lineMove:
+			if err := rec.validateMove(*l.MoveUpdate); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			rec.Updates = append(rec.Updates, *l.MoveUpdate)
+		case l.Type == lineResult:
+			rec.Summary = *l.Summary
+			sawResult = true
+		default:
+			return nil, fmt.Errorf("line %d: unknown line type %q", lineNo, l.Type)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !sawHeader {
+		return nil, errors.New("empty recording")
+	}
+	if !sawResult {
+		return nil, errors.New("recording is truncated: missing result line")
+	}
+	if err := rec.checkPath(); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// checkBounds rejects headers whose board exceeds MaxBoardSide, before the
+// board is allocated.
+func checkBounds(h Header) error {
+	if len(h.Options.Shape) == 0 {
+		if h.Size > MaxBoardSide {
+			return fmt.Errorf("board size %d exceeds %d", h.Size, MaxBoardSide)
+		}
+		return nil
+	}
+	for i, r := range h.Options.Shape {
+		// Compared one by one so the sums cannot overflow
+		if r.X > MaxBoardSide || r.Rows > MaxBoardSide || r.X+r.Rows > MaxBoardSide ||
+			r.Y > MaxBoardSide || r.Cols > MaxBoardSide || r.Y+r.Cols > MaxBoardSide {
+			return fmt.Errorf("rectangle %d extends past %dx%d", i, MaxBoardSide, MaxBoardSide)
+		}
+	}
+	return nil
+}
+
+// checkPath checks the path the updates leave on the board against the
+// board invariants, and that a run marked successful covers the board.
+func (rec *Recording) checkPath() error {
+	b, err := rec.Header.Options.Board(rec.Header.Size)
+	if err != nil {
+		return err
+	}
+	for i, pos := range rec.Path() {
+		if b[pos.X][pos.Y] != 0 {
+			return fmt.Errorf("path revisits (%d, %d) at move %d", pos.X, pos.Y, i+1)
+		}
+		b.WriteToBoard(pos, i+1)
+	}
+	if err := b.CheckInvariants(); err != nil {
+		return fmt.Errorf("path is not a knight's path: %w", err)
+	}
+	if rec.Summary.Success && !b.IsComplete() {
+		return errors.New("recording claims success but its path does not cover the board")
+	}
+	return nil
+}
+
+// validateMove checks that an update stays on the recorded board.
+func (rec *Recording) validateMove(update solver.MoveUpdate) error {
+	pos := update.Position
+	if !rec.board.Contains(pos) {
+		return fmt.Errorf("move (%d, %d) is off the board", pos.X, pos.Y)
+	}
+	if !update.IsBacktrack && (update.MoveNumber < 1 || update.MoveNumber > rec.squares) {
+		return fmt.Errorf("move number %d out of range", update.MoveNumber)
+	}
+	return nil
+}
+
+// Path replays the updates and returns the squares still on the knight's path
+// at the end of the run: the full tour for successful runs.
+func (rec *Recording) Path() []board.Position {
+	var path []board.Position
+	for _, update := range rec.Updates {
+		if update.IsDeadEnd {
+			continue
+		}
+		if update.IsBacktrack {
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+			continue
+		}
+		path = append(path, update.Position)
+	}
+	return path
+}
+
+// Result converts the recording into the SolveResult the solver returned.
+func (rec *Recording) Result() *solver.SolveResult {
+	result := &solver.SolveResult{
+		Success:      rec.Summary.Success,
+		AttemptCount: rec.Summary.AttemptCount,
+		DeadEnds:     rec.Summary.DeadEnds,
+	}
+	if rec.Summary.Success {
+		for i, pos := range rec.Path() {
+			result.Moves = append(result.Moves, solver.MoveUpdate{Position: pos, MoveNumber: i + 1})
+		}
+	}
+	return result
+}
+
+// Verify re-runs the recorded solve and reports the first divergence between
+// the recording and the fresh run, if any. The re-run stops at the first
+// mismatch and never makes more attempts than the recording, so a run cut
+// short by its deadline verifies in the same number of steps.
+func (rec *Recording) Verify(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	opts := rec.Header.Options
+	attempts := rec.Summary.AttemptCount
+	if rec.stoppedByContext() {
+		// The solver counted the attempt that noticed the deadline but did
+		// nothing in it; an attempt limit stops one attempt earlier in the
+		// same state
+		attempts--
+		if attempts <= 0 {
+			if len(rec.Updates) > 0 {
+				return fmt.Errorf("recording has %d updates but stopped before its first attempt", len(rec.Updates))
+			}
+			return nil
+		}
+	}
+	opts.MaxAttempts = attempts
+	index := 0
+	var mismatch error
+	opts.OnMove = func(update solver.MoveUpdate) {
+		if mismatch != nil {
+			return
+		}
+		defer func() {
+			if mismatch != nil {
+				cancel()
+			}
+		}()
+		if index >= len(rec.Updates) {
+			mismatch = fmt.Errorf("update %d: run continues past the end of the recording", index+1)
+			return
+		}
+		if rec.Updates[index] != update {
+			mismatch = fmt.Errorf("update %d: recorded %+v, replayed %+v", index+1, rec.Updates[index], update)
+		}
+		index++
+	}
+
+	result, err := solver.NewSolver().SolveWithOptions(ctx, rec.Header.Size, rec.Header.StartPos, opts)
+	if mismatch != nil {
+		return mismatch
+	}
+	if err != nil && err != solver.ErrAttemptLimit {
+		return err
+	}
+	if index != len(rec.Updates) {
+		return fmt.Errorf("run ended after %d updates, recording has %d", index, len(rec.Updates))
+	}
+	if result.Success != rec.Summary.Success || result.AttemptCount != attempts {
+		return fmt.Errorf("result differs: recorded success=%t attempts=%d, replayed success=%t attempts=%d",
+			rec.Summary.Success, rec.Summary.AttemptCount, result.Success, result.AttemptCount)
+	}
+	return nil
+}
+
+// stoppedByContext reports whether the recorded run was cut short by its
+// deadline or a cancellation.
+func (rec *Recording) stoppedByContext() bool {
+	return rec.Summary.Error == context.DeadlineExceeded.Error() || rec.Summary.Error == context.Canceled.Error()
+}
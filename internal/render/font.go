@@ -0,0 +1,80 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// glyphWidth and glyphHeight are the dimensions of the bitmap font, in font pixels.
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// glyphs is a tiny 3x5 bitmap font covering the characters needed for board
+// coordinates (files a-z, aa, ab..., ranks in digits). Each row is 3 bits,
+// most significant bit left.
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 3, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 2, 2, 2},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'a': {0, 7, 1, 7, 7},
+	'b': {4, 4, 7, 5, 7},
+	'c': {0, 7, 4, 4, 7},
+	'd': {1, 1, 7, 5, 7},
+	'e': {0, 7, 7, 4, 7},
+	'f': {3, 4, 6, 4, 4},
+	'g': {7, 5, 7, 1, 6},
+	'h': {4, 4, 7, 5, 5},
+	'i': {2, 0, 2, 2, 2},
+	'j': {1, 0, 1, 5, 7},
+	'k': {4, 5, 6, 5, 5},
+	'l': {6, 2, 2, 2, 7},
+	'm': {0, 7, 7, 5, 5},
+	'n': {0, 6, 5, 5, 5},
+	'o': {0, 7, 5, 5, 7},
+	'p': {0, 7, 5, 7, 4},
+	'q': {0, 7, 5, 7, 1},
+	'r': {0, 7, 4, 4, 4},
+	's': {0, 3, 6, 1, 6},
+	't': {2, 7, 2, 2, 3},
+	'u': {0, 5, 5, 5, 7},
+	'v': {0, 5, 5, 5, 2},
+	'w': {0, 5, 5, 7, 7},
+	'x': {0, 5, 2, 2, 5},
+	'y': {0, 5, 7, 1, 6},
+	'z': {0, 7, 1, 4, 7},
+}
+
+// textWidth returns the width in image pixels of text drawn at the given scale.
+func textWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * scale
+}
+
+// drawText draws text with its top-left corner at (x, y). Unknown runes are skipped.
+func drawText(img *image.RGBA, x, y int, text string, scale int, c color.NRGBA) {
+	for _, r := range text {
+		glyph, ok := glyphs[r]
+		if ok {
+			for row := 0; row < glyphHeight; row++ {
+				for col := 0; col < glyphWidth; col++ {
+					if glyph[row]&(1<<(glyphWidth-1-col)) != 0 {
+						fillRect(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
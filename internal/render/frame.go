@@ -0,0 +1,217 @@
+// Package render draws knight's tours as themed raster images.
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+
+	"the_knight/pkg/board"
+)
+
+// Square size limits, in pixels.
+const (
+	DefaultSquareSize = 48
+	MinSquareSize     = 16
+	MaxSquareSize     = 128
+)
+
+// MaxFrameSide bounds the width and height of a frame, in pixels. Boards too
+// large for it at the requested square size are drawn with smaller squares.
+const MaxFrameSide = 4096
+
+// Options controls how a frame is drawn.
+type Options struct {
+	Theme      Theme
+	SquareSize int // pixels per square, clamped to [MinSquareSize, MaxSquareSize] and shrunk to fit MaxFrameSide
+}
+
+// knightSprite is a 12x12 silhouette of a knight, facing left.
+var knightSprite = [12]string{
+	"....XX......",
+	"...XXXX.....",
+	"..XXXXXX....",
+	".XXXXX.XX...",
+	"XXXXXXXXXX..",
+	"XXX.XXXXXX..",
+	"....XXXXX...",
+	"...XXXXX....",
+	"..XXXXXX....",
+	"..XXXXXXX...",
+	".XXXXXXXXX..",
+	".XXXXXXXXX..",
+}
+
+// Frame draws board b showing the first n moves of path, with the knight
+// standing on move n. Only the squares of b are drawn, so composite boards
+// keep their shape. Ranks and files are labelled chess style: files a, b, c...
+// left to right (Y) and ranks counted from the bottom row (X).
+func Frame(b board.Board, path []board.Position, n int, opts Options) *image.RGBA {
+	sq := opts.SquareSize
+	if sq == 0 {
+		sq = DefaultSquareSize
+	}
+	if sq < MinSquareSize {
+		sq = MinSquareSize
+	}
+	if sq > MaxSquareSize {
+		sq = MaxSquareSize
+	}
+	rows, cols := b.Dimensions()
+	// The frame is max(rows, cols)*sq plus a margin of sq/2
+	if fit := 2 * MaxFrameSide / (2*max(rows, cols) + 1); sq > fit {
+		sq = max(fit, 1)
+	}
+	if n > len(path) {
+		n = len(path)
+	}
+	if n < 0 {
+		n = 0
+	}
+	theme := opts.Theme
+
+	margin := sq / 2
+	img := image.NewRGBA(image.Rect(0, 0, cols*sq+margin, rows*sq+margin))
+	fillRect(img, img.Bounds(), theme.Frame)
+
+	// cell returns the pixel rectangle of a board square.
+	cell := func(pos board.Position) image.Rectangle {
+		x0 := margin + pos.Y*sq
+		y0 := pos.X * sq
+		return image.Rect(x0, y0, x0+sq, y0+sq)
+	}
+
+	// Squares, using the same light/dark rule as the web UI.
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			if b[x][y] == board.Blocked {
+				continue
+			}
+			c := theme.Dark
+			if (x+y)%2 == 0 {
+				c = theme.Light
+			}
+			fillRect(img, cell(board.Position{X: x, Y: y}), c)
+		}
+	}
+
+	// Visited squares and the knight's current square.
+	for i := 0; i < n; i++ {
+		if i == n-1 {
+			fillRect(img, cell(path[i]), theme.Current)
+		} else {
+			blendRect(img, cell(path[i]), theme.Visited)
+		}
+	}
+
+	// Path connecting the square centers.
+	if n > 1 {
+		mask := image.NewAlpha(img.Bounds())
+		thickness := sq / 16
+		if thickness < 2 {
+			thickness = 2
+		}
+		center := func(pos board.Position) image.Point {
+			r := cell(pos)
+			return image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+		}
+		for i := 1; i < n; i++ {
+			drawLine(mask, center(path[i-1]), center(path[i]), thickness)
+		}
+		draw.DrawMask(img, img.Bounds(), image.NewUniform(theme.Path), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	if n > 0 {
+		drawKnight(img, cell(path[n-1]), theme)
+	}
+
+	// Coordinates: ranks in the left margin, files in the bottom margin.
+	scale := margin / 10
+	if scale < 1 {
+		scale = 1
+	}
+	textHeight := glyphHeight * scale
+	for x := 0; x < rows; x++ {
+		label := strconv.Itoa(rows - x)
+		r := cell(board.Position{X: x, Y: 0})
+		drawText(img, (margin-textWidth(label, scale))/2, r.Min.Y+(sq-textHeight)/2, label, scale, theme.Coordinates)
+	}
+	for y := 0; y < cols; y++ {
+		label := board.FileName(y)
+		// Files past z take two letters: shrink them to their square
+		s := scale
+		for s > 1 && textWidth(label, s) > sq {
+			s--
+		}
+		r := cell(board.Position{X: rows - 1, Y: y})
+		drawText(img, r.Min.X+(sq-textWidth(label, s))/2, rows*sq+(margin-glyphHeight*s)/2, label, s, theme.Coordinates)
+	}
+
+	return img
+}
+
+// drawKnight draws the knight sprite centered in the given square.
+func drawKnight(img *image.RGBA, square image.Rectangle, theme Theme) {
+	rows := len(knightSprite)
+	cols := len(knightSprite[0])
+	span := square.Dx() * 3 / 4
+	x0 := square.Min.X + (square.Dx()-span)/2
+	y0 := square.Min.Y + (square.Dy()-span)/2
+
+	filled := func(r, c int) bool {
+		return r >= 0 && r < rows && c >= 0 && c < cols && knightSprite[r][c] == 'X'
+	}
+
+	for py := 0; py < span; py++ {
+		for px := 0; px < span; px++ {
+			r := py * rows / span
+			c := px * cols / span
+			if !filled(r, c) {
+				continue
+			}
+			col := theme.Knight
+			if !filled(r-1, c) || !filled(r+1, c) || !filled(r, c-1) || !filled(r, c+1) {
+				col = theme.KnightEdge
+			}
+			img.Set(x0+px, y0+py, col)
+		}
+	}
+}
+
+// drawLine rasterises a thick line into an alpha mask.
+func drawLine(mask *image.Alpha, from, to image.Point, thickness int) {
+	dx := to.X - from.X
+	dy := to.Y - from.Y
+	steps := abs(dx)
+	if abs(dy) > steps {
+		steps = abs(dy)
+	}
+	half := thickness / 2
+	for i := 0; i <= steps; i++ {
+		x := from.X
+		y := from.Y
+		if steps > 0 {
+			x += dx * i / steps
+			y += dy * i / steps
+		}
+		draw.Draw(mask, image.Rect(x-half, y-half, x-half+thickness, y-half+thickness), image.Opaque, image.Point{}, draw.Src)
+	}
+}
+
+// fillRect paints a rectangle with an opaque color.
+func fillRect(img *image.RGBA, r image.Rectangle, c color.NRGBA) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// blendRect composites a translucent color over a rectangle.
+func blendRect(img *image.RGBA, r image.Rectangle, c color.NRGBA) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
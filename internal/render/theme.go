@@ -0,0 +1,84 @@
+package render
+
+import (
+	"image/color"
+	"sort"
+)
+
+// Theme describes the colors used to draw a board frame.
+type Theme struct {
+	Name        string
+	Light       color.NRGBA // light squares
+	Dark        color.NRGBA // dark squares
+	Visited     color.NRGBA // tint blended over visited squares
+	Current     color.NRGBA // square occupied by the knight
+	Path        color.NRGBA // line connecting the moves
+	Frame       color.NRGBA // border around the board holding the coordinates
+	Coordinates color.NRGBA // file/rank labels
+	Knight      color.NRGBA // knight sprite fill
+	KnightEdge  color.NRGBA // knight sprite outline
+}
+
+// DefaultTheme is used when no (or an unknown) theme is requested.
+const DefaultTheme = "green"
+
+// themes holds the built-in palettes, modelled after the popular chess sites.
+var themes = map[string]Theme{
+	// Chess.com style green board
+	"green": {
+		Name:        "green",
+		Light:       color.NRGBA{0xee, 0xee, 0xd2, 0xff},
+		Dark:        color.NRGBA{0x76, 0x96, 0x56, 0xff},
+		Visited:     color.NRGBA{0xba, 0xca, 0x44, 0x70},
+		Current:     color.NRGBA{0xf6, 0xf6, 0x69, 0xff},
+		Path:        color.NRGBA{0x2b, 0x2b, 0x2b, 0xc0},
+		Frame:       color.NRGBA{0x31, 0x2e, 0x2b, 0xff},
+		Coordinates: color.NRGBA{0xee, 0xee, 0xd2, 0xff},
+		Knight:      color.NRGBA{0xf9, 0xf9, 0xf9, 0xff},
+		KnightEdge:  color.NRGBA{0x1f, 0x1f, 0x1f, 0xff},
+	},
+	// Lichess style brown board
+	"brown": {
+		Name:        "brown",
+		Light:       color.NRGBA{0xf0, 0xd9, 0xb5, 0xff},
+		Dark:        color.NRGBA{0xb5, 0x88, 0x63, 0xff},
+		Visited:     color.NRGBA{0x9b, 0xc7, 0x00, 0x60},
+		Current:     color.NRGBA{0xcd, 0xd2, 0x6a, 0xff},
+		Path:        color.NRGBA{0x15, 0x78, 0x1b, 0xc0},
+		Frame:       color.NRGBA{0x2e, 0x2a, 0x24, 0xff},
+		Coordinates: color.NRGBA{0xf0, 0xd9, 0xb5, 0xff},
+		Knight:      color.NRGBA{0xff, 0xff, 0xff, 0xff},
+		KnightEdge:  color.NRGBA{0x00, 0x00, 0x00, 0xff},
+	},
+	// The purple palette of the web UI
+	"cyber": {
+		Name:        "cyber",
+		Light:       color.NRGBA{0x1a, 0x0a, 0x2e, 0xff},
+		Dark:        color.NRGBA{0x0f, 0x0a, 0x1a, 0xff},
+		Visited:     color.NRGBA{0x93, 0x33, 0xea, 0x90},
+		Current:     color.NRGBA{0xc0, 0x84, 0xfc, 0xff},
+		Path:        color.NRGBA{0xe0, 0xb0, 0xff, 0xc0},
+		Frame:       color.NRGBA{0x0a, 0x0a, 0x0f, 0xff},
+		Coordinates: color.NRGBA{0xb7, 0x94, 0xf6, 0xff},
+		Knight:      color.NRGBA{0xe0, 0xb0, 0xff, 0xff},
+		KnightEdge:  color.NRGBA{0x6b, 0x21, 0xa8, 0xff},
+	},
+}
+
+// LookupTheme returns the named theme, falling back to DefaultTheme.
+func LookupTheme(name string) Theme {
+	if theme, ok := themes[name]; ok {
+		return theme
+	}
+	return themes[DefaultTheme]
+}
+
+// ThemeNames lists the built-in theme names in alphabetical order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,42 @@
+package solver
+
+import "fmt"
+
+// Levels of the lines a solve sends to SolveOptions.OnLog.
+const (
+	LogInfo  = "info"  // start, outcome and stop reason of the solve
+	LogDebug = "debug" // heuristic decisions, prunes and dead ends (with SolveOptions.LogDebug)
+)
+
+// logf formats a line for SolveOptions.OnLog. Callers on the search path
+// check debugging first, so a solve without debug logging formats nothing.
+func (s *Solver) logf(level, format string, args ...any) {
+	if s.opts.OnLog == nil {
+		return
+	}
+	s.opts.OnLog(level, fmt.Sprintf(format, args...))
+}
+
+// debugging reports whether debug lines are wanted.
+func (s *Solver) debugging() bool {
+	return s.opts.OnLog != nil && s.opts.LogDebug
+}
+
+// logOutcome logs how a solve ended.
+func (s *Solver) logOutcome(result *SolveResult, err error) {
+	switch {
+	case result.Success:
+		s.logf(LogInfo, "Tour found after %d attempts (%d dead ends, about %d bytes)", result.AttemptCount, result.DeadEnds, result.MemoryBytes)
+	case err != nil:
+		s.logf(LogInfo, "Search stopped after %d attempts (%d dead ends): %v", result.AttemptCount, result.DeadEnds, err)
+	default:
+		s.logf(LogInfo, "Search exhausted after %d attempts (%d dead ends): no tour from this square", result.AttemptCount, result.DeadEnds)
+	}
+}
+
+func algorithmName(algorithm string) string {
+	if algorithm == "" {
+		return AlgorithmWarnsdorff
+	}
+	return algorithm
+}
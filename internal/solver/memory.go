@@ -0,0 +1,60 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrMemoryBudget is matched (with errors.Is) by the *MemoryBudgetError a solve
+// returns when its estimated memory use exceeds SolveOptions.MemoryBudget.
+var ErrMemoryBudget = errors.New("solver: memory budget exceeded")
+
+// Structures whose memory a solve accounts for.
+const (
+	MemoryBoard      = "board"      // the board and its degree counts
+	MemoryMoves      = "moves"      // the move log
+	MemoryCandidates = "candidates" // the per-depth candidate buffers
+	MemoryTree       = "tree"       // the recorded search tree
+)
+
+// MemoryBudgetError describes a solve stopped by its memory budget.
+type MemoryBudgetError struct {
+	Budget int64 `json:"budget"` // bytes allowed
+	Used   int64 `json:"used"`   // estimated bytes in use once the last allocation was counted
+	// Component is the structure whose allocation crossed the budget
+	Component string `json:"component"`
+}
+
+func (e *MemoryBudgetError) Error() string {
+	return fmt.Sprintf("solver: memory budget exceeded: %d of %d bytes in use after growing the %s", e.Used, e.Budget, e.Component)
+}
+
+// Is makes errors.Is(err, ErrMemoryBudget) work.
+func (e *MemoryBudgetError) Is(target error) bool {
+	return target == ErrMemoryBudget
+}
+
+// Estimated sizes, in bytes, of the units the solver allocates.
+var (
+	cellBytes      = int64(unsafe.Sizeof(int(0)))
+	moveBytes      = int64(unsafe.Sizeof(MoveUpdate{}))
+	candidateBytes = int64(unsafe.Sizeof([candidateChunk][8]moveCandidate{}))
+	treeNodeBytes  = int64(unsafe.Sizeof(TreeNode{}) + unsafe.Sizeof(int(0))) // node plus its path entry
+)
+
+// charge adds an allocation to the running estimate. Over budget it records
+// a *MemoryBudgetError as the stop reason, so the search unwinds, and returns it.
+func (s *Solver) charge(component string, bytes int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.memUsed += bytes
+	if s.opts.MemoryBudget <= 0 || s.memUsed <= s.opts.MemoryBudget {
+		return nil
+	}
+	err := &MemoryBudgetError{Budget: s.opts.MemoryBudget, Used: s.memUsed, Component: component}
+	if s.stopErr == nil {
+		s.stopErr = err
+	}
+	return err
+}
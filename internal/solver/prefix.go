@@ -0,0 +1,89 @@
+package solver
+
+import (
+	"fmt"
+
+	"the_knight/pkg/board"
+)
+
+// Prefixes splits the top of the search tree: it returns the paths of depth
+// moves after start, each the root of a subtree that SolveOptions.Prefix
+// searches on its own. Together the subtrees cover every tour from start.
+// They come in the order the search would try them (Warnsdorff order unless
+// opts selects backtracking; the seed is ignored), and paths that dead-end
+// before depth are left out. depth is capped at the moves a tour needs.
+func Prefixes(boardSize int, start board.Position, opts SolveOptions, depth int) ([][]board.Position, error) {
+	b, err := opts.Board(boardSize)
+	if err != nil {
+		return nil, fmt.Errorf("solver: %w", err)
+	}
+	if !b.Contains(start) {
+		return nil, fmt.Errorf("solver: start (%d, %d) is not on the board", start.X, start.Y)
+	}
+	depth = max(0, min(depth, b.SquareCount()-1))
+
+	var prefixes [][]board.Position
+	path := make([]board.Position, 0, depth)
+	var walk func(pos board.Position, moveNumber int)
+	walk = func(pos board.Position, moveNumber int) {
+		if len(path) == depth {
+			prefixes = append(prefixes, append([]board.Position(nil), path...))
+			return
+		}
+		b.WriteToBoard(pos, moveNumber)
+		defer b.ClearPosition(pos)
+
+		var candidates []moveCandidate
+		for _, move := range knightMoves {
+			next := board.Position{X: pos.X + move.X, Y: pos.Y + move.Y}
+			if b.IsValidMove(next) {
+				candidates = append(candidates, moveCandidate{position: next, accessibility: b.CountValidMoves(next)})
+			}
+		}
+		if opts.Algorithm != AlgorithmBacktracking {
+			// Stable, like the insertion sort of the search
+			for i := 1; i < len(candidates); i++ {
+				for j := i; j > 0 && candidates[j-1].accessibility > candidates[j].accessibility; j-- {
+					candidates[j-1], candidates[j] = candidates[j], candidates[j-1]
+				}
+			}
+		}
+		for _, c := range candidates {
+			path = append(path, c.position)
+			walk(c.position, moveNumber+1)
+			path = path[:len(path)-1]
+		}
+	}
+	walk(start, 1)
+	return prefixes, nil
+}
+
+// checkPrefix validates SolveOptions.Prefix: distinct squares of the board,
+// each a knight's move from the one before, starting next to start.
+func checkPrefix(b board.Board, start board.Position, prefix []board.Position) error {
+	seen := map[board.Position]bool{start: true}
+	prev := start
+	for i, pos := range prefix {
+		switch {
+		case !b.Contains(pos):
+			return fmt.Errorf("prefix move %d (%d, %d) is not on the board", i+2, pos.X, pos.Y)
+		case seen[pos]:
+			return fmt.Errorf("prefix move %d (%d, %d) revisits a square", i+2, pos.X, pos.Y)
+		case !isKnightMove(prev, pos):
+			return fmt.Errorf("prefix move %d (%d, %d) is not a knight's move from (%d, %d)", i+2, pos.X, pos.Y, prev.X, prev.Y)
+		}
+		seen[pos] = true
+		prev = pos
+	}
+	return nil
+}
+
+// pinned narrows the candidates of a prefix depth to the prefix square.
+func pinned(candidates []moveCandidate, pos board.Position) []moveCandidate {
+	for _, c := range candidates {
+		if c.position == pos {
+			return append(candidates[:0], c)
+		}
+	}
+	return candidates[:0]
+}
@@ -2,6 +2,9 @@ package solver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
 	"sync"
 	"the_knight/pkg/board"
 	"time"
@@ -19,6 +22,61 @@ type Solver struct {
 	moves []MoveUpdate
 	// attemptCount tracks recursive calls
 	attemptCount int
+	// deadEnds counts squares the knight got stuck on
+	deadEnds int
+	// memUsed is the estimated memory allocated for the solve in progress, in bytes
+	memUsed int64
+	// opts holds the options of the solve in progress
+	opts SolveOptions
+	// stopErr is set once the search has to unwind: the context error or ErrAttemptLimit
+	stopErr error
+	// rng shuffles candidates before ranking when opts.Seed is set
+	rng *rand.Rand
+	// start is the first square of the solve in progress (needed for closed tours)
+	start board.Position
+	// tree records the explored search tree when opts.RecordTree is set
+	tree *SearchTree
+	// candidateBufs holds one candidate buffer per search depth, allocated in
+	// chunks of candidateChunk depths. A frame only uses its own depth's
+	// buffer, so buffers are reused across siblings and solves instead of
+	// allocating a slice at every node.
+	candidateBufs []*[candidateChunk][8]moveCandidate
+}
+
+// candidateChunk is the number of search depths whose candidate buffers are allocated together.
+const candidateChunk = 64
+
+// moveCandidate is a square the knight may jump to next, with its Warnsdorff rank.
+type moveCandidate struct {
+	position      board.Position
+	accessibility int
+}
+
+// ErrAttemptLimit is returned when a search gives up at SolveOptions.MaxAttempts
+// without having proven that no tour exists.
+var ErrAttemptLimit = errors.New("solver: attempt limit reached")
+
+// cancelCheckInterval is the number of attempts between context checks.
+// Once the context is done, every frame on the stack unwinds without trying
+// its remaining candidates, so cancellation latency no longer depends on the
+// branching factor.
+const cancelCheckInterval = 64
+
+// knightMoves holds the offsets of all 8 possible knight moves.
+var knightMoves = []board.Position{
+	{X: 2, Y: -1}, {X: 2, Y: 1}, {X: -2, Y: 1}, {X: -2, Y: -1},
+	{X: 1, Y: 2}, {X: 1, Y: -2}, {X: -1, Y: 2}, {X: -1, Y: -2},
+}
+
+// searchBoard is the board API the search relies on. board.Board rescans the
+// neighbours of a square on every CountValidMoves call; *board.DegreeBoard
+// answers from counts it updates as squares are written and cleared.
+type searchBoard interface {
+	WriteToBoard(pos board.Position, moveNumber int)
+	ClearPosition(pos board.Position)
+	IsValidMove(pos board.Position) bool
+	CountValidMoves(pos board.Position) int
+	IsComplete() bool
 }
 
 // NewSolver creates a new solver instance with properly sized channels.
@@ -31,14 +89,88 @@ func NewSolver() *Solver {
 }
 
 // Solve attempts to find a knight's tour solution using Warnsdorff's heuristic.
-// It runs in a separate goroutine and communicates via channels.
+// It runs in a separate goroutine and streams every move on the move channel.
 func (s *Solver) Solve(ctx context.Context, boardSize int, startPos board.Position) (*SolveResult, error) {
+	return s.SolveWithOptions(ctx, boardSize, startPos, SolveOptions{StreamMoves: true})
+}
+
+// SolveWithOptions is Solve with explicit options.
+// With StreamMoves off it has no consumer requirements, which makes it usable
+// from the CLI and the WASM build.
+func (s *Solver) SolveWithOptions(ctx context.Context, boardSize int, startPos board.Position, opts SolveOptions) (*SolveResult, error) {
+	result, err := s.solve(ctx, boardSize, startPos, opts)
+	// Invalid requests fail before the solve starts and have nothing to log
+	if result != nil {
+		s.logOutcome(result, err)
+	}
+	return result, err
+}
+
+func (s *Solver) solve(ctx context.Context, boardSize int, startPos board.Position, opts SolveOptions) (*SolveResult, error) {
+	// Create a fresh board for this solve
+	grid, err := opts.Board(boardSize)
+	if err != nil {
+		return nil, fmt.Errorf("solver: %w", err)
+	}
+	if !grid.Contains(startPos) {
+		return nil, fmt.Errorf("solver: start (%d, %d) is not on the board", startPos.X, startPos.Y)
+	}
+	if err := checkPrefix(grid, startPos, opts.Prefix); err != nil {
+		return nil, fmt.Errorf("solver: %w", err)
+	}
+
 	// Clear previous state
 	s.mu.Lock()
 	s.moves = s.moves[:0]
 	s.attemptCount = 0
+	s.deadEnds = 0
+	s.memUsed = 0
+	s.opts = opts
+	s.stopErr = nil
+	s.start = startPos
+	s.tree = nil
+	if opts.RecordTree {
+		s.tree = newSearchTree(opts.TreeNodeCap)
+	}
+	s.rng = nil
+	if opts.Seed != 0 {
+		s.rng = rand.New(rand.NewSource(opts.Seed))
+	}
 	s.mu.Unlock()
 
+	// Account for what is allocated up front, so a board too large for the
+	// budget is rejected before the search starts
+	rows, cols := grid.Dimensions()
+	squares := grid.SquareCount()
+	s.logf(LogInfo, "Solving %dx%d board (%d squares) from (%d, %d): algorithm %s, closed %t, seed %d, attempt limit %d, memory budget %d bytes",
+		rows, cols, squares, startPos.X, startPos.Y, algorithmName(opts.Algorithm), opts.Closed, opts.Seed, opts.MaxAttempts, opts.MemoryBudget)
+	if len(opts.Prefix) > 0 {
+		s.logf(LogInfo, "Searching only below a prefix of %d moves", len(opts.Prefix))
+	}
+	for _, alloc := range []struct {
+		component string
+		bytes     int64
+	}{
+		{MemoryBoard, int64(rows*cols) * cellBytes * 2},
+		{MemoryMoves, int64(squares) * moveBytes},
+		{MemoryCandidates, int64(len(s.candidateBufs)) * candidateBytes},
+	} {
+		if err := s.charge(alloc.component, alloc.bytes); err != nil {
+			return &SolveResult{MemoryBytes: s.getMemUsed()}, err
+		}
+	}
+
+	var b searchBoard = board.NewDegreeBoard(grid)
+	if opts.ScanDegrees {
+		b = grid
+	}
+	// The move log never grows past one entry per square
+	if cap(s.moves) < squares {
+		s.mu.Lock()
+		s.moves = make([]MoveUpdate, 0, squares)
+		s.mu.Unlock()
+	}
+
 	// Drain channels to ensure clean state
 	s.clearChannels()
 
@@ -49,16 +181,15 @@ func (s *Solver) Solve(ctx context.Context, boardSize int, startPos board.Positi
 
 	wg.Add(1)
 
-	// Create a fresh board for this solve
-	b := board.NewBoard(boardSize)
-
 	go func() {
 		defer wg.Done()
-		success = s.solveRecursive(ctx, b, startPos, 1)
+		// The result is reported through doneChan only; writing success here
+		// would race with the receive below.
+		found := s.solveRecursive(ctx, b, startPos, 1)
 		// Signal completion (success or failure)
 		// Note: solveRecursive sends doneChan internally when solution found,
 		// but we need to ensure it's sent for failure case too
-		if !success {
+		if !found {
 			select {
 			case s.doneChan <- false:
 			case <-ctx.Done():
@@ -88,6 +219,9 @@ func (s *Solver) Solve(ctx context.Context, boardSize int, startPos board.Positi
 		return &SolveResult{
 			Success:      false,
 			AttemptCount: s.getAttemptCount(),
+			DeadEnds:     s.getDeadEnds(),
+			MemoryBytes:  s.getMemUsed(),
+			Tree:         s.tree,
 		}, solveErr
 	}
 
@@ -106,31 +240,45 @@ func (s *Solver) Solve(ctx context.Context, boardSize int, startPos board.Positi
 		s.clearChannels()
 	}
 
-	return &SolveResult{
+	result := &SolveResult{
 		Success:      success,
 		Moves:        finalMoves,
 		AttemptCount: s.getAttemptCount(),
-	}, nil
+		DeadEnds:     s.getDeadEnds(),
+		MemoryBytes:  s.getMemUsed(),
+		Tree:         s.tree,
+	}
+	// The search may have unwound because of the context even though the
+	// failure signal won the race above; report why it stopped.
+	if !success {
+		if err := s.stopReason(); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
 }
 
 // solveRecursive implements the recursive backtracking algorithm with Warnsdorff's heuristic.
-func (s *Solver) solveRecursive(ctx context.Context, b board.Board, currentPos board.Position, moveNumber int) bool {
-	// Check for context cancellation
-	select {
-	case <-ctx.Done():
+func (s *Solver) solveRecursive(ctx context.Context, b searchBoard, currentPos board.Position, moveNumber int) (found bool) {
+	// Counts the attempt and periodically checks for cancellation
+	if s.incAttemptCount(ctx) {
 		return false
-	default:
 	}
 
-	s.incAttemptCount()
-
 	// Mark the current position
 	b.WriteToBoard(currentPos, moveNumber)
 
-	// Send move update (non-blocking with buffered channel)
-	select {
-	case s.moveChan <- MoveUpdate{Position: currentPos, MoveNumber: moveNumber, IsBacktrack: false}:
-	case <-ctx.Done():
+	deadEnd := false
+	if s.tree != nil {
+		node := s.tree.enter(currentPos, moveNumber)
+		defer func() { s.tree.leave(node, s.nodeOutcome(found, deadEnd)) }()
+		if node >= 0 && s.charge(MemoryTree, treeNodeBytes) != nil {
+			return false
+		}
+	}
+
+	// Publish move update
+	if !s.emit(ctx, MoveUpdate{Position: currentPos, MoveNumber: moveNumber, IsBacktrack: false}) {
 		return false
 	}
 
@@ -139,8 +287,9 @@ func (s *Solver) solveRecursive(ctx context.Context, b board.Board, currentPos b
 	s.moves = append(s.moves, MoveUpdate{Position: currentPos, MoveNumber: moveNumber, IsBacktrack: false})
 	s.mu.Unlock()
 
-	// Check if board is complete
-	if b.IsComplete() {
+	// Check if board is complete; a closed tour must also end next to the start.
+	// An open ending simply finds no candidates below and backtracks.
+	if b.IsComplete() && (!s.opts.Closed || isKnightMove(currentPos, s.start)) {
 		select {
 		case s.doneChan <- true:
 		case <-ctx.Done():
@@ -149,19 +298,16 @@ func (s *Solver) solveRecursive(ctx context.Context, b board.Board, currentPos b
 		return true
 	}
 
-	// Knight move offsets (all 8 possible moves)
-	knightMoves := []board.Position{
-		{X: 2, Y: -1}, {X: 2, Y: 1}, {X: -2, Y: 1}, {X: -2, Y: -1},
-		{X: 1, Y: 2}, {X: 1, Y: -2}, {X: -1, Y: 2}, {X: -1, Y: -2},
-	}
+	// A closed tour needs an unvisited square next to the start for its last move
+	closedOff := s.opts.Closed && b.CountValidMoves(s.start) == 0
+	debug := s.debugging()
 
-	// Warnsdorff's heuristic: collect and sort by accessibility
-	type MoveCandidate struct {
-		position      board.Position
-		accessibility int
+	// Collect candidates; Warnsdorff's heuristic sorts them by accessibility
+	candidates, err := s.candidateBuffer(moveNumber)
+	if err != nil {
+		return false
 	}
-
-	var candidates []MoveCandidate
+	ruledOut := 0
 
 	for _, move := range knightMoves {
 		newPos := board.Position{
@@ -169,17 +315,44 @@ func (s *Solver) solveRecursive(ctx context.Context, b board.Board, currentPos b
 			Y: currentPos.Y + move.Y,
 		}
 
-		if b.IsValidMove(newPos) {
-			accessibility := b.CountValidMoves(newPos)
-			candidates = append(candidates, MoveCandidate{
-				position:      newPos,
-				accessibility: accessibility,
-			})
+		if !b.IsValidMove(newPos) {
+			continue
+		}
+		if closedOff {
+			ruledOut++
+			continue
+		}
+		candidates = append(candidates, moveCandidate{position: newPos})
+	}
+	// A closed search that covers the board but cannot close is not stuck
+	// with squares left: it backtracks without a dead-end event
+	full := b.IsComplete()
+	deadEnd = len(candidates) == 0 && !full
+	if debug && full {
+		s.logf(LogDebug, "Move %d at (%d, %d) covers the board but cannot close the tour, backtracking", moveNumber, currentPos.X, currentPos.Y)
+	}
+	if debug && ruledOut > 0 {
+		s.logf(LogDebug, "Move %d at (%d, %d): pruned %d candidates, the start square has no free neighbour left for the closing move",
+			moveNumber, currentPos.X, currentPos.Y, ruledOut)
+	}
+
+	// A seeded shuffle decides the order of ties; the sort below is stable
+	if s.rng != nil {
+		s.rng.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	}
+
+	// Plain backtracking keeps the (possibly shuffled) move order
+	heuristic := s.opts.Algorithm != AlgorithmBacktracking
+	if heuristic {
+		for i := range candidates {
+			candidates[i].accessibility = b.CountValidMoves(candidates[i].position)
 		}
 	}
 
 	// Sort by accessibility (insertion sort for small lists)
-	for i := 1; i < len(candidates); i++ {
+	for i := 1; heuristic && i < len(candidates); i++ {
 		key := candidates[i]
 		j := i - 1
 		for j >= 0 && candidates[j].accessibility > key.accessibility {
@@ -188,21 +361,50 @@ func (s *Solver) solveRecursive(ctx context.Context, b board.Board, currentPos b
 		}
 		candidates[j+1] = key
 	}
+	// A prefix pins the first moves; only its square is tried at its depth
+	if moveNumber <= len(s.opts.Prefix) {
+		candidates = pinned(candidates, s.opts.Prefix[moveNumber-1])
+	}
+	if debug && len(candidates) > 0 {
+		first := candidates[0]
+		if heuristic {
+			s.logf(LogDebug, "Move %d at (%d, %d): %d candidates, trying (%d, %d) first with %d onward moves",
+				moveNumber, currentPos.X, currentPos.Y, len(candidates), first.position.X, first.position.Y, first.accessibility)
+		} else {
+			s.logf(LogDebug, "Move %d at (%d, %d): %d candidates, trying (%d, %d) first",
+				moveNumber, currentPos.X, currentPos.Y, len(candidates), first.position.X, first.position.Y)
+		}
+	}
 
 	// Try moves in priority order
 	for _, candidate := range candidates {
 		if s.solveRecursive(ctx, b, candidate.position, moveNumber+1) {
 			return true
 		}
+		// Unwind immediately instead of trying the remaining candidates
+		if s.stopReason() != nil {
+			return false
+		}
+	}
+
+	// Nowhere to go from here: report the dead end before backtracking
+	if deadEnd {
+		s.mu.Lock()
+		s.deadEnds++
+		s.mu.Unlock()
+		if debug {
+			s.logf(LogDebug, "Dead end at (%d, %d) after move %d, backtracking", currentPos.X, currentPos.Y, moveNumber)
+		}
+		if !s.emit(ctx, MoveUpdate{Position: currentPos, MoveNumber: moveNumber, IsDeadEnd: true, CandidatesTried: ruledOut}) {
+			return false
+		}
 	}
 
 	// Backtrack: clear position and remove from moves
 	b.ClearPosition(currentPos)
 
-	// Send backtrack update
-	select {
-	case s.moveChan <- MoveUpdate{Position: currentPos, MoveNumber: 0, IsBacktrack: true}:
-	case <-ctx.Done():
+	// Publish backtrack update
+	if !s.emit(ctx, MoveUpdate{Position: currentPos, MoveNumber: 0, IsBacktrack: true}) {
 		return false
 	}
 
@@ -216,6 +418,60 @@ func (s *Solver) solveRecursive(ctx context.Context, b board.Board, currentPos b
 	return false
 }
 
+// candidateBuffer returns the empty candidate buffer of a search depth.
+// It is only touched by the solving goroutine, so it needs no locking.
+// Growing the buffers counts against the memory budget.
+func (s *Solver) candidateBuffer(depth int) ([]moveCandidate, error) {
+	for len(s.candidateBufs) <= depth/candidateChunk {
+		if err := s.charge(MemoryCandidates, candidateBytes); err != nil {
+			return nil, err
+		}
+		s.candidateBufs = append(s.candidateBufs, new([candidateChunk][8]moveCandidate))
+	}
+	return s.candidateBufs[depth/candidateChunk][depth%candidateChunk][:0], nil
+}
+
+// emit hands an update to the OnMove observer and, when streaming, to the move channel.
+// It returns false if the context was cancelled while waiting on the channel.
+func (s *Solver) emit(ctx context.Context, update MoveUpdate) bool {
+	if s.opts.OnMove != nil {
+		s.opts.OnMove(update)
+	}
+	if !s.opts.StreamMoves {
+		return true
+	}
+	select {
+	case s.moveChan <- update:
+		return true
+	case <-ctx.Done():
+		s.mu.Lock()
+		if s.stopErr == nil {
+			s.stopErr = ctx.Err()
+		}
+		s.mu.Unlock()
+		return false
+	}
+}
+
+// nodeOutcome classifies a search tree node as it is left.
+func (s *Solver) nodeOutcome(found, deadEnd bool) string {
+	switch {
+	case found:
+		return NodeSolution
+	case s.stopReason() != nil:
+		return NodeStopped
+	case deadEnd:
+		return NodeDeadEnd
+	}
+	return NodeBacktrack
+}
+
+// isKnightMove reports whether a knight can jump directly between two squares.
+func isKnightMove(from, to board.Position) bool {
+	dx, dy := from.X-to.X, from.Y-to.Y
+	return dx*dx+dy*dy == 5
+}
+
 // GetMoveChannel returns the channel for receiving move updates.
 // Used by the web server to stream moves to clients.
 func (s *Solver) GetMoveChannel() <-chan MoveUpdate {
@@ -241,10 +497,54 @@ doneMoves:
 	}
 }
 
-func (s *Solver) incAttemptCount() {
+// incAttemptCount counts a recursive call and reports whether the search must stop,
+// either because the attempt limit was reached or because the context is done.
+// The context is only consulted every cancelCheckInterval attempts.
+func (s *Solver) incAttemptCount(ctx context.Context) bool {
 	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopErr != nil {
+		return true
+	}
+	if s.opts.MaxAttempts > 0 && s.attemptCount >= s.opts.MaxAttempts {
+		s.stopErr = ErrAttemptLimit
+		return true
+	}
 	s.attemptCount++
-	s.mu.Unlock()
+	if s.attemptCount == 1 || s.attemptCount%cancelCheckInterval == 0 {
+		if err := ctx.Err(); err != nil {
+			s.stopErr = err
+			return true
+		}
+	}
+	return false
+}
+
+// stopReason returns why the search is unwinding, or nil while it is running.
+func (s *Solver) stopReason() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stopErr
+}
+
+// Progress reports the attempts made so far and the length of the current path.
+// It is safe to call while a solve is running.
+func (s *Solver) Progress() (attempts, depth int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.attemptCount, len(s.moves)
+}
+
+func (s *Solver) getMemUsed() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.memUsed
+}
+
+func (s *Solver) getDeadEnds() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deadEnds
 }
 
 func (s *Solver) getAttemptCount() int {
@@ -0,0 +1,118 @@
+package solver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"the_knight/pkg/board"
+)
+
+// hardSolve starts a search that runs far longer than any test: plain
+// backtracking on 8x8 from a corner.
+func hardSolve(ctx context.Context, opts SolveOptions) (*SolveResult, error) {
+	opts.Algorithm = AlgorithmBacktracking
+	return NewSolver().SolveWithOptions(ctx, 8, board.Position{X: 0, Y: 0}, opts)
+}
+
+func TestSolveCancelIsPrompt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	began := time.Now()
+	result, err := hardSolve(ctx, SolveOptions{})
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(began); elapsed > time.Second {
+		t.Errorf("solve returned %v after the start, long after the cancel at 20ms", elapsed)
+	}
+	if result == nil || result.Success || result.AttemptCount == 0 {
+		t.Errorf("result = %+v, want an unsuccessful result with the attempts made", result)
+	}
+}
+
+func TestSolveDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := hardSolve(ctx, SolveOptions{})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if result == nil || result.Success {
+		t.Errorf("result = %+v, want an unsuccessful result", result)
+	}
+}
+
+func TestSolveUnwindsAtCancelCheckInterval(t *testing.T) {
+	// The context is consulted at the start of every cancelCheckInterval-th
+	// attempt, so a cancel during attempt n stops the search at the next
+	// multiple after n
+	for _, n := range []int{1, 100, 3 * cancelCheckInterval} {
+		ctx, cancel := context.WithCancel(context.Background())
+		slv := NewSolver()
+		opts := SolveOptions{Algorithm: AlgorithmBacktracking}
+		opts.OnMove = func(MoveUpdate) {
+			if attempts, _ := slv.Progress(); attempts >= n {
+				cancel()
+			}
+		}
+
+		result, err := slv.SolveWithOptions(ctx, 8, board.Position{X: 0, Y: 0}, opts)
+		cancel()
+		if err != context.Canceled {
+			t.Fatalf("cancel at %d: err = %v, want context.Canceled", n, err)
+		}
+		want := (n/cancelCheckInterval + 1) * cancelCheckInterval
+		if result.AttemptCount != want {
+			t.Errorf("cancel at %d: stopped after %d attempts, want %d", n, result.AttemptCount, want)
+		}
+	}
+}
+
+func TestClosedSearchReportsNoDeadEndOnFullBoard(t *testing.T) {
+	// A single square covers the board at move 1 but cannot close
+	for _, size := range []int{1, 6} {
+		var deadEnds []MoveUpdate
+		opts := SolveOptions{Closed: true, MaxAttempts: 100_000, OnMove: func(update MoveUpdate) {
+			if update.IsDeadEnd {
+				deadEnds = append(deadEnds, update)
+			}
+		}}
+		result, err := NewSolver().SolveWithOptions(context.Background(), size, board.Position{}, opts)
+		if err != nil && err != ErrAttemptLimit {
+			t.Fatalf("%dx%d: %v", size, size, err)
+		}
+		for _, update := range deadEnds {
+			if update.MoveNumber == size*size {
+				t.Errorf("%dx%d: dead end reported on the full board: %+v", size, size, update)
+			}
+		}
+		if result.DeadEnds != len(deadEnds) {
+			t.Errorf("%dx%d: DeadEnds = %d, want the %d events", size, size, result.DeadEnds, len(deadEnds))
+		}
+	}
+}
+
+// BenchmarkSolve50 compares Warnsdorff on 50x50 with the board's degree
+// cache against rescanning the neighbours of every candidate.
+func BenchmarkSolve50(b *testing.B) {
+	for _, mode := range []struct {
+		name string
+		scan bool
+	}{
+		{"degree-cache", false},
+		{"scan-degrees", true},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				result, err := NewSolver().SolveWithOptions(context.Background(), 50, board.Position{}, SolveOptions{ScanDegrees: mode.scan})
+				if err != nil || !result.Success {
+					b.Fatalf("50x50 solve: success=%t err=%v", result != nil && result.Success, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+
+	"the_knight/pkg/board"
+)
+
+// DefaultTreeNodeCap bounds the recorded search tree when SolveOptions.TreeNodeCap is 0.
+const DefaultTreeNodeCap = 10000
+
+// Outcomes of a search tree node.
+const (
+	NodeSolution  = "solution"  // on the path of the tour that was found
+	NodeBacktrack = "backtrack" // explored and abandoned
+	NodeDeadEnd   = "deadend"   // abandoned with no move left to try
+	NodeStopped   = "stopped"   // still open when the search was cancelled or limited
+)
+
+// TreeNode is one square visited by the search.
+type TreeNode struct {
+	ID       int            `json:"id"`
+	Parent   int            `json:"parent"` // -1 for the root
+	Position board.Position `json:"position"`
+	Depth    int            `json:"depth"` // move number of the square
+	Outcome  string         `json:"outcome"`
+}
+
+// SearchTree is the part of the search tree explored by a solve, in visiting order.
+type SearchTree struct {
+	Nodes []TreeNode `json:"nodes"`
+	// Truncated is set when the node cap was reached; nodes visited after that were not recorded.
+	Truncated bool `json:"truncated"`
+
+	// stack holds the IDs of the nodes on the current path (-1 for unrecorded nodes)
+	stack []int
+	cap   int
+}
+
+func newSearchTree(nodeCap int) *SearchTree {
+	if nodeCap <= 0 {
+		nodeCap = DefaultTreeNodeCap
+	}
+	return &SearchTree{cap: nodeCap}
+}
+
+// enter records a visit to pos below the current path and returns its node ID,
+// or -1 if the cap has been reached.
+func (t *SearchTree) enter(pos board.Position, depth int) int {
+	parent := -1
+	if len(t.stack) > 0 {
+		parent = t.stack[len(t.stack)-1]
+	}
+
+	id := -1
+	if len(t.Nodes) < t.cap {
+		id = len(t.Nodes)
+		t.Nodes = append(t.Nodes, TreeNode{ID: id, Parent: parent, Position: pos, Depth: depth, Outcome: NodeStopped})
+	} else {
+		t.Truncated = true
+	}
+	t.stack = append(t.stack, id)
+	return id
+}
+
+// leave closes the node on top of the path with the given outcome.
+func (t *SearchTree) leave(id int, outcome string) {
+	t.stack = t.stack[:len(t.stack)-1]
+	if id >= 0 {
+		t.Nodes[id].Outcome = outcome
+	}
+}
+
+// nodeColors maps outcomes to Graphviz colors.
+var nodeColors = map[string]string{
+	NodeSolution:  "forestgreen",
+	NodeBacktrack: "firebrick",
+	NodeDeadEnd:   "black",
+	NodeStopped:   "gray50",
+}
+
+// WriteDOT writes the tree in Graphviz DOT format.
+func (t *SearchTree) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph search {"); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "  node [shape=box, style=filled, fontcolor=white, fontname=monospace];")
+	if t.Truncated {
+		fmt.Fprintf(w, "  label=\"truncated at %d nodes\";\n", len(t.Nodes))
+	}
+	for _, n := range t.Nodes {
+		fmt.Fprintf(w, "  n%d [label=\"%d: (%d,%d)\", fillcolor=%s];\n", n.ID, n.Depth, n.Position.X, n.Position.Y, nodeColors[n.Outcome])
+		if n.Parent >= 0 {
+			fmt.Fprintf(w, "  n%d -> n%d;\n", n.Parent, n.ID)
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
@@ -1,13 +1,27 @@
 package solver
 
-import "the_knight/pkg/board"
+import (
+	"fmt"
+
+	"the_knight/pkg/board"
+)
 
 // MoveUpdate represents a single move in the knight's tour.
 // Sent through channels to track progress in real-time.
+//
+// Besides moves and backtracks the stream carries dead-end events (IsDeadEnd):
+// the knight stands on Position at depth MoveNumber and cannot continue
+// although squares remain unvisited. The backtrack of that square follows.
+// A closed-tour search that covers the board without ending next to the start
+// is not a dead end: it backtracks without the event.
 type MoveUpdate struct {
 	Position    board.Position
 	MoveNumber  int
 	IsBacktrack bool // true if this move is being backtracked (cleared)
+	IsDeadEnd   bool `json:",omitempty"`
+	// CandidatesTried counts the onward moves considered at a dead end and
+	// ruled out; it is non-zero only when closed-tour pruning rejected them.
+	CandidatesTried int `json:",omitempty"`
 }
 
 // SolveResult encapsulates the result of a solve attempt.
@@ -15,4 +29,85 @@ type SolveResult struct {
 	Success      bool
 	Moves        []MoveUpdate
 	AttemptCount int
+	// DeadEnds counts the dead-end events of the search
+	DeadEnds int
+	// MemoryBytes estimates the memory the solve allocated for its board,
+	// move log, candidate buffers and search tree
+	MemoryBytes int64
+	// Tree is the explored search tree, set only when SolveOptions.RecordTree is on
+	Tree *SearchTree `json:",omitempty"`
+}
+
+// MaxBoardSize is the largest square board the web server and the WASM
+// build solve.
+const MaxBoardSize = 200
+
+// Search algorithms understood by the solver.
+const (
+	// AlgorithmWarnsdorff orders candidates by Warnsdorff's heuristic (default).
+	AlgorithmWarnsdorff = "warnsdorff"
+	// AlgorithmBacktracking tries candidates in fixed move order (brute force).
+	AlgorithmBacktracking = "backtracking"
+)
+
+// IsValidAlgorithm reports whether name is a known algorithm. The empty string
+// selects the default.
+func IsValidAlgorithm(name string) bool {
+	return name == "" || name == AlgorithmWarnsdorff || name == AlgorithmBacktracking
+}
+
+// SolveOptions tunes a single solve run.
+// The zero value runs an unbounded search without publishing on the move channel.
+type SolveOptions struct {
+	// StreamMoves publishes every MoveUpdate on the move channel.
+	// Leave it off when nothing drains the channel (CLI, WASM), otherwise
+	// the solver blocks as soon as the buffer fills up.
+	StreamMoves bool `json:"streamMoves"`
+	// Algorithm selects the search strategy; empty means AlgorithmWarnsdorff.
+	Algorithm string `json:"algorithm"`
+	// Closed requires the last square to be a knight's move away from the start.
+	Closed bool `json:"closed,omitempty"`
+	// Seed, when non-zero, breaks ties between equally ranked candidates with a
+	// pseudo-random order derived from it. The same seed always replays the same search.
+	Seed int64 `json:"seed,omitempty"`
+	// MaxAttempts stops the search after this many recursive calls (0 = no limit).
+	MaxAttempts int `json:"maxAttempts"`
+	// RecordTree keeps the explored search tree, up to TreeNodeCap nodes
+	// (DefaultTreeNodeCap when 0), and returns it with the result.
+	RecordTree  bool `json:"recordTree,omitempty"`
+	TreeNodeCap int  `json:"treeNodeCap,omitempty"`
+	// Shape solves on a composite board made of these rectangles instead of
+	// a size x size board. Moves may cross the seams between rectangles.
+	Shape []board.Rect `json:"shape,omitempty"`
+	// Prefix pins the first moves after the start square, so the search only
+	// explores the subtree below them (see Prefixes). Distributed solves hand
+	// each worker a different prefix.
+	Prefix []board.Position `json:"prefix,omitempty"`
+	// MemoryBudget stops the search with a *MemoryBudgetError once the
+	// estimated memory of the solve exceeds this many bytes (0 = no limit).
+	MemoryBudget int64 `json:"memoryBudget,omitempty"`
+	// ScanDegrees ranks candidates by rescanning their neighbours instead of
+	// using the board's incremental degree counts. The search is identical;
+	// it only exists to benchmark the cache.
+	ScanDegrees bool `json:"-"`
+	// OnMove is called synchronously from the solving goroutine for every update.
+	OnMove func(MoveUpdate) `json:"-"`
+	// OnLog receives the log lines of the solve (see LogInfo and LogDebug),
+	// synchronously from the solving goroutine.
+	OnLog func(level, message string) `json:"-"`
+	// LogDebug also sends OnLog a line for every heuristic decision, prune
+	// and dead end. Expect one or more lines per attempt.
+	LogDebug bool `json:"logDebug,omitempty"`
+}
+
+// Board returns a fresh board for a solve with these options: the composite
+// Shape when set, a size x size board otherwise.
+func (o SolveOptions) Board(size int) (board.Board, error) {
+	if len(o.Shape) > 0 {
+		return board.NewComposite(o.Shape)
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid board size %d", size)
+	}
+	return board.NewBoard(size), nil
 }
@@ -0,0 +1,111 @@
+// Package store persists small JSON documents as one file per key in a
+// directory. Writes are atomic (write to a temporary file, then rename), so a
+// crash never leaves a half-written document behind.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileExtension is appended to every key on disk.
+const fileExtension = ".json"
+
+// Dir is a directory of JSON documents.
+type Dir struct {
+	path string
+}
+
+// Open returns the store in path, creating the directory if needed.
+func Open(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+	return &Dir{path: path}, nil
+}
+
+// Put stores v as JSON under key, replacing any previous document.
+func (d *Dir) Put(key string, v any) error {
+	file, err := d.file(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(d.path, "."+key+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// Delete removes the document stored under key. Missing keys are not an error.
+func (d *Dir) Delete(key string) error {
+	file, err := d.file(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Keys returns the keys of all stored documents in sorted order.
+func (d *Dir) Keys() ([]string, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, fileExtension) {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(name, fileExtension))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Get decodes the document stored under key into v.
+func (d *Dir) Get(key string, v any) error {
+	file, err := d.file(key)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// file maps a key to its path, rejecting keys that could escape the directory.
+func (d *Dir) file(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("store: invalid key %q", key)
+	}
+	return filepath.Join(d.path, key+fileExtension), nil
+}
@@ -0,0 +1,141 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"the_knight/internal/analysis"
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// handleHeat reports which start squares admit open and closed tours.
+// GET /api/analysis/heat?size=8[&format=svg][&budget=N]
+func (s *Server) handleHeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	size, err := strconv.Atoi(query.Get("size"))
+	if err != nil || size <= 0 || size > analysis.MaxHeatSize {
+		http.Error(w, fmt.Sprintf("size must be between 1 and %d", analysis.MaxHeatSize), http.StatusBadRequest)
+		return
+	}
+	budget := 0
+	if v := query.Get("budget"); v != "" {
+		if budget, err = strconv.Atoi(v); err != nil || budget < 0 || budget > analysis.DefaultHeatBudget {
+			http.Error(w, fmt.Sprintf("budget must be between 0 and %d", analysis.DefaultHeatBudget), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), defaultSolveTimeout)
+	defer cancel()
+
+	report, err := analysis.Heat(ctx, size, budget)
+	switch {
+	case err == context.DeadlineExceeded:
+		http.Error(w, "Analysis timed out", http.StatusRequestTimeout)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if query.Get("format") == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(report.SVG())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleEstimate scores how hard a solve is expected to be before it is
+// launched, so clients can warn about an expensive or hopeless one. The
+// request takes the board and start fields of /api/solve plus the solver
+// options that change the search.
+// POST /api/estimate {"size": 8, "startPos": {"X": 0, "Y": 0}, "algorithm": "warnsdorff", "closed": false, "seed": 0, "samples": 8}
+func (s *Server) handleEstimate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Size        int             `json:"size"`
+		StartPos    json.RawMessage `json:"startPos"`
+		Coordinates string          `json:"coordinates"`
+		Shape       string          `json:"shape"`
+		Rects       []board.Rect    `json:"rects"`
+		Algorithm   string          `json:"algorithm"`
+		Closed      bool            `json:"closed"`
+		Seed        int64           `json:"seed"`
+		// Samples is the number of restarts with other seeds (0 = default)
+		Samples int `json:"samples"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.Size <= 0 || req.Size > maxSolveSize {
+		http.Error(w, fmt.Sprintf("size must be between 1 and %d", maxSolveSize), http.StatusBadRequest)
+		return
+	}
+	if !board.IsValidCoordinates(req.Coordinates) {
+		http.Error(w, fmt.Sprintf("Unknown coordinates %q", req.Coordinates), http.StatusBadRequest)
+		return
+	}
+	if !solver.IsValidAlgorithm(req.Algorithm) {
+		http.Error(w, fmt.Sprintf("Unknown algorithm %q", req.Algorithm), http.StatusBadRequest)
+		return
+	}
+	if req.Samples < 0 || req.Samples > analysis.MaxEstimateSamples {
+		http.Error(w, fmt.Sprintf("samples must be between 0 and %d", analysis.MaxEstimateSamples), http.StatusBadRequest)
+		return
+	}
+
+	rects, err := compositeRects(req.Shape, req.Size, req.Rects)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid board shape: %v", err), http.StatusBadRequest)
+		return
+	}
+	opts := solver.SolveOptions{Algorithm: req.Algorithm, Closed: req.Closed, Seed: req.Seed, Shape: rects}
+	b, err := opts.Board(req.Size)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid board shape: %v", err), http.StatusBadRequest)
+		return
+	}
+	rows, _ := b.Dimensions()
+	startPos, err := decodePosition(req.StartPos, req.Coordinates, rows)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid start position: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !b.Contains(startPos) {
+		http.Error(w, "Start position is not on the board", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), defaultSolveTimeout)
+	defer cancel()
+
+	estimate, err := analysis.Estimate(ctx, req.Size, startPos, opts, req.Samples)
+	switch {
+	case err == context.DeadlineExceeded:
+		http.Error(w, "Estimate timed out", http.StatusRequestTimeout)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(estimate)
+}
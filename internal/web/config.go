@@ -0,0 +1,85 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Config gathers the optional modes of a server, as set by the environment
+// (ConfigFromEnv) or the flags of the serve command.
+type Config struct {
+	// Addr is the address to listen on, e.g. ":8080"
+	Addr string
+	// Kiosk serves the read-only gallery instead of solving (see EnableKiosk)
+	Kiosk bool
+	// Workers are the base URLs a coordinator splits solves over (see EnableWorkers)
+	Workers []string
+	// Worker runs subtrees for coordinators, WorkerSlots at a time (see EnableWorkerMode)
+	Worker      bool
+	WorkerToken string
+	WorkerSlots int
+	// DataDir persists unfinished solves (see EnableJobRecovery)
+	DataDir string
+}
+
+// ConfigFromEnv reads the configuration from PORT (default 8080), KIOSK,
+// WORKERS (comma-separated), WORKER, WORKER_TOKEN, WORKER_SLOTS (default one
+// per CPU) and DATA_DIR.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Addr:        ":8080",
+		Kiosk:       os.Getenv("KIOSK") != "",
+		Worker:      os.Getenv("WORKER") != "",
+		WorkerToken: os.Getenv("WORKER_TOKEN"),
+		WorkerSlots: runtime.NumCPU(),
+		DataDir:     os.Getenv("DATA_DIR"),
+	}
+	if port := os.Getenv("PORT"); port != "" {
+		cfg.Addr = ":" + port
+	}
+	if workers := os.Getenv("WORKERS"); workers != "" {
+		cfg.Workers = strings.Split(workers, ",")
+	}
+	if v := os.Getenv("WORKER_SLOTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("WORKER_SLOTS: %w", err)
+		}
+		cfg.WorkerSlots = n
+	}
+	return cfg, nil
+}
+
+// Configure enables the modes of cfg. A kiosk runs no solves, so it cannot be
+// combined with workers, worker mode or a data directory.
+func (s *Server) Configure(cfg Config) error {
+	if cfg.Kiosk && (cfg.DataDir != "" || len(cfg.Workers) > 0 || cfg.Worker) {
+		return errors.New("kiosk mode cannot be combined with workers, worker mode or a data directory: a kiosk runs no solves")
+	}
+	if cfg.Kiosk {
+		if err := s.EnableKiosk(); err != nil {
+			return fmt.Errorf("kiosk: %w", err)
+		}
+	}
+	if len(cfg.Workers) > 0 {
+		if err := s.EnableWorkers(cfg.Workers, cfg.WorkerToken); err != nil {
+			return fmt.Errorf("workers: %w", err)
+		}
+	}
+	if cfg.Worker {
+		if err := s.EnableWorkerMode(cfg.WorkerToken, cfg.WorkerSlots); err != nil {
+			return fmt.Errorf("worker: %w", err)
+		}
+	}
+	// Last: recovered solves start right away and need the configuration above
+	if cfg.DataDir != "" {
+		if err := s.EnableJobRecovery(cfg.DataDir); err != nil {
+			return fmt.Errorf("job recovery: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// Positions are exchanged in matrix coordinates ({"X": row, "Y": column},
+// row 0 at the top) unless a client asks for chess coordinates, either with
+// "coordinates": "chess" in a request body or ?coordinates=chess on a GET.
+// In chess coordinates every position becomes a square name such as "e4";
+// the JSON shapes stay the same otherwise.
+
+// coordinatesParam returns the convention requested in the query string, or fallback.
+func coordinatesParam(r *http.Request, fallback string) (string, error) {
+	name := r.URL.Query().Get("coordinates")
+	if name == "" {
+		name = fallback
+	}
+	if !board.IsValidCoordinates(name) {
+		return "", fmt.Errorf("unknown coordinates %q (want %s or %s)", name, board.CoordinatesMatrix, board.CoordinatesChess)
+	}
+	if name == "" {
+		name = board.CoordinatesMatrix
+	}
+	return name, nil
+}
+
+// decodePosition reads a request position in the given convention: an
+// {"X","Y"} object for matrix coordinates, a square name for chess coordinates.
+// A missing position is the top-left square.
+func decodePosition(raw json.RawMessage, coordinates string, rows int) (board.Position, error) {
+	var pos board.Position
+	if len(raw) == 0 || string(raw) == "null" {
+		return pos, nil
+	}
+	if coordinates != board.CoordinatesChess {
+		err := json.Unmarshal(raw, &pos)
+		return pos, err
+	}
+	var square string
+	if err := json.Unmarshal(raw, &square); err != nil {
+		return pos, fmt.Errorf("chess coordinates expect a square name like \"a8\": %w", err)
+	}
+	return board.ParseSquare(square, rows)
+}
+
+// chessMove is a MoveUpdate with its position as a square name.
+type chessMove struct {
+	Position        string
+	MoveNumber      int
+	IsBacktrack     bool
+	IsDeadEnd       bool `json:",omitempty"`
+	CandidatesTried int  `json:",omitempty"`
+}
+
+func toChessMove(move solver.MoveUpdate, rows int) chessMove {
+	return chessMove{
+		Position:        move.Position.Square(rows),
+		MoveNumber:      move.MoveNumber,
+		IsBacktrack:     move.IsBacktrack,
+		IsDeadEnd:       move.IsDeadEnd,
+		CandidatesTried: move.CandidatesTried,
+	}
+}
+
+// chessResult is a SolveResult in chess coordinates. Its Moves field shadows
+// the embedded one when encoded.
+type chessResult struct {
+	*solver.SolveResult
+	Moves []chessMove
+}
+
+func toChessResult(result *solver.SolveResult, rows int) *chessResult {
+	if result == nil {
+		return nil
+	}
+	view := &chessResult{SolveResult: result, Moves: make([]chessMove, len(result.Moves))}
+	for i, move := range result.Moves {
+		view.Moves[i] = toChessMove(move, rows)
+	}
+	return view
+}
+
+// moveView returns a move update for encoding in the given convention.
+func moveView(move solver.MoveUpdate, coordinates string, rows int) any {
+	if coordinates == board.CoordinatesChess {
+		return toChessMove(move, rows)
+	}
+	return move
+}
+
+// resultView returns a solve result for encoding in the given convention.
+func resultView(result *solver.SolveResult, coordinates string, rows int) any {
+	if coordinates == board.CoordinatesChess {
+		return toChessResult(result, rows)
+	}
+	return result
+}
+
+// tourView returns a tour for encoding in the given convention.
+func tourView(t tourRecord, coordinates string) any {
+	t.Coordinates = coordinates
+	if coordinates != board.CoordinatesChess {
+		return t
+	}
+	rows := t.rows()
+	return struct {
+		tourRecord
+		StartPos string       `json:"startPos"`
+		Result   *chessResult `json:"result,omitempty"`
+	}{t, t.StartPos.Square(rows), toChessResult(t.Result, rows)}
+}
+
+// summariesView returns tour summaries for encoding in the given convention.
+func summariesView(summaries []tourSummary, coordinates string) any {
+	if coordinates != board.CoordinatesChess {
+		return summaries
+	}
+	type chessSummary struct {
+		tourSummary
+		StartPos string `json:"startPos"`
+	}
+	views := make([]chessSummary, len(summaries))
+	for i, summary := range summaries {
+		views[i] = chessSummary{summary, summary.StartPos.Square(summary.rows)}
+	}
+	return views
+}
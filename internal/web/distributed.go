@@ -0,0 +1,111 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"the_knight/internal/distributed"
+	"the_knight/internal/solver"
+)
+
+// EnableWorkers makes the server a coordinator: solves requested with
+// "distributed": true are split into subtrees and searched by the workers
+// at these base URLs (instances in worker mode sharing token) instead of
+// locally.
+func (s *Server) EnableWorkers(urls []string, token string) error {
+	if token == "" {
+		return errors.New("coordinator mode needs a token shared with the workers")
+	}
+	c, err := distributed.NewCoordinator(urls, token)
+	if err != nil {
+		return err
+	}
+	s.coordinator = c
+	log.Printf("Coordinator mode: distributing solves over %d workers", len(c.Workers()))
+	return nil
+}
+
+// EnableWorkerMode lets coordinators presenting token run subtrees on this
+// server, at most slots at a time; further tasks are refused with 503.
+func (s *Server) EnableWorkerMode(token string, slots int) error {
+	if token == "" {
+		return errors.New("worker mode needs a token shared with the coordinator")
+	}
+	if slots <= 0 {
+		return fmt.Errorf("worker slots must be positive, got %d", slots)
+	}
+	s.workerToken = token
+	s.workerSlots = make(chan struct{}, slots)
+	log.Printf("Worker mode: running up to %d subtrees at a time for coordinators", slots)
+	return nil
+}
+
+// handleWorkerSolve searches one subtree for a coordinator, in worker mode
+// only. The coordinator cancels the task by dropping the request.
+// POST /api/worker/solve {"size": 8, "startPos": {"X": 0, "Y": 0}, "options": {"prefix": [...]}}
+func (s *Server) handleWorkerSolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.workerSlots == nil {
+		http.Error(w, "Worker mode is not enabled", http.StatusNotFound)
+		return
+	}
+	if !distributed.Authorize(r, s.workerToken) {
+		http.Error(w, "Invalid worker token", http.StatusUnauthorized)
+		return
+	}
+
+	var task distributed.Task
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if task.Size <= 0 || task.Size > maxSolveSize {
+		http.Error(w, fmt.Sprintf("size must be between 1 and %d", maxSolveSize), http.StatusBadRequest)
+		return
+	}
+	if !solver.IsValidAlgorithm(task.Options.Algorithm) {
+		http.Error(w, fmt.Sprintf("Unknown algorithm %q", task.Options.Algorithm), http.StatusBadRequest)
+		return
+	}
+	if err := checkCompositeSide(task.Options.Shape); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid board shape: %v", err), http.StatusBadRequest)
+		return
+	}
+	if _, err := task.Options.Board(task.Size); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid board shape: %v", err), http.StatusBadRequest)
+		return
+	}
+	if task.Options.MemoryBudget <= 0 || task.Options.MemoryBudget > maxMemoryBudget {
+		task.Options.MemoryBudget = defaultMemoryBudget
+	}
+
+	select {
+	case s.workerSlots <- struct{}{}:
+		defer func() { <-s.workerSlots }()
+	default:
+		http.Error(w, "All worker slots are busy", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), maxSolveTimeout)
+	defer cancel()
+
+	out, err := distributed.Work(ctx, task)
+	if r.Context().Err() != nil {
+		// The coordinator gave up on the task; nobody reads the answer
+		return
+	}
+	if err != nil {
+		out.Error = fmt.Sprintf("subtree not searched within %v", maxSolveTimeout)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,56 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"the_knight/internal/render"
+)
+
+// handleFrame renders a still of a tour at a given move as a PNG image.
+// GET /api/tours/{id}/frame/{n}.png?theme=green|brown|cyber&square=48
+func (s *Server) handleFrame(w http.ResponseWriter, r *http.Request, tour tourRecord, file string) {
+	if !strings.HasSuffix(file, ".png") {
+		http.NotFound(w, r)
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(file, ".png"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid move index: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if tour.Status != statusSolved {
+		http.Error(w, fmt.Sprintf("Tour is %s, frames are only available for solved tours", tour.Status), http.StatusConflict)
+		return
+	}
+	path := tour.path()
+	if n < 0 || n > len(path) {
+		http.Error(w, fmt.Sprintf("Move index must be between 0 and %d", len(path)), http.StatusBadRequest)
+		return
+	}
+
+	opts := render.Options{Theme: render.LookupTheme(r.URL.Query().Get("theme"))}
+	if sq := r.URL.Query().Get("square"); sq != "" {
+		if opts.SquareSize, err = strconv.Atoi(sq); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid square size: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Encode into a buffer first so encoding errors still produce a proper status code
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, render.Frame(tour.board(), path, n, opts)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	// Solved tours never change, so frames can be cached aggressively
+	w.Header().Set("Cache-Control", "public, max-age=86400, immutable")
+	w.Write(buf.Bytes())
+}
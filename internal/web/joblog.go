@@ -0,0 +1,106 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"the_knight/internal/solver"
+)
+
+// jobLogCap is the number of lines a job keeps per level; older lines are dropped first.
+const jobLogCap = 1000
+
+// logLine is one captured log line of a job.
+type logLine struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// logRing keeps the most recent jobLogCap lines.
+type logRing struct {
+	lines   []logLine
+	next    int // index of the oldest line once the ring is full
+	dropped int
+}
+
+func (r *logRing) add(line logLine) {
+	if len(r.lines) < jobLogCap {
+		r.lines = append(r.lines, line)
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % jobLogCap
+	r.dropped++
+}
+
+// ordered returns the lines oldest first.
+func (r *logRing) ordered() []logLine {
+	lines := make([]logLine, 0, len(r.lines))
+	lines = append(lines, r.lines[r.next:]...)
+	return append(lines, r.lines[:r.next]...)
+}
+
+// jobLog holds the recent log lines of a solve: the solver's own lines (see
+// solver.SolveOptions.OnLog) and the server's about the job, so a slow or
+// failed solve can be diagnosed without access to the server's output.
+// Debug lines get a ring of their own so a flood of them never pushes out
+// the start and outcome of the solve.
+type jobLog struct {
+	mu          sync.Mutex
+	info, debug logRing
+}
+
+// add appends a line; it has the signature of solver.SolveOptions.OnLog.
+func (l *jobLog) add(level, message string) {
+	line := logLine{Time: time.Now(), Level: level, Message: message}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level == solver.LogDebug {
+		l.debug.add(line)
+	} else {
+		l.info.add(line)
+	}
+}
+
+// addf adds a formatted info line.
+func (l *jobLog) addf(format string, args ...any) {
+	l.add(solver.LogInfo, fmt.Sprintf(format, args...))
+}
+
+// snapshot returns the kept lines, oldest first, and how many were dropped.
+// Without debug only the info lines are returned.
+func (l *jobLog) snapshot(debug bool) ([]logLine, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	info := l.info.ordered()
+	if !debug {
+		return info, l.info.dropped
+	}
+	lines := append(info, l.debug.ordered()...)
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	return lines, l.info.dropped + l.debug.dropped
+}
+
+// handleLogs returns the captured log of a solve; ?level=info leaves out the
+// debug lines of solves started with "debugLog": true.
+// GET /api/tours/{id}/logs
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request, tour tourRecord) {
+	if tour.log == nil {
+		http.Error(w, "No log was captured for this tour", http.StatusNotFound)
+		return
+	}
+	level := r.URL.Query().Get("level")
+	if level != "" && level != solver.LogInfo && level != solver.LogDebug {
+		http.Error(w, fmt.Sprintf("level must be %s or %s", solver.LogInfo, solver.LogDebug), http.StatusBadRequest)
+		return
+	}
+
+	lines, dropped := tour.log.snapshot(level != solver.LogInfo)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"lines": lines, "dropped": dropped})
+}
@@ -0,0 +1,184 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/internal/store"
+	"the_knight/pkg/board"
+)
+
+// jobDescriptor is the persisted form of a solve: everything needed to start
+// it again after the server restarts.
+type jobDescriptor struct {
+	ID        string              `json:"id"`
+	Size      int                 `json:"size"`
+	StartPos  board.Position      `json:"startPos"`
+	Options   solver.SolveOptions `json:"options"`
+	TimeoutMs int64               `json:"timeoutMs"`
+	CreatedAt time.Time           `json:"createdAt"`
+	// Restarts counts how many times the job was picked up again after a restart
+	Restarts int `json:"restarts"`
+	// Coordinates is the convention the solve was requested in
+	Coordinates string `json:"coordinates,omitempty"`
+	// Distributed runs the solve on the coordinator's workers
+	Distributed bool `json:"distributed,omitempty"`
+}
+
+// maxJobRestarts bounds how often a solve is resumed after a restart. A
+// solve that takes the server down with it (a crash or an out-of-memory
+// kill) would otherwise do so again on every start.
+const maxJobRestarts = 3
+
+func (d jobDescriptor) timeout() time.Duration {
+	return time.Duration(d.TimeoutMs) * time.Millisecond
+}
+
+// jobManager persists the descriptors of unfinished solves so they survive a
+// restart. Without a store (the default) it keeps nothing.
+type jobManager struct {
+	store *store.Dir
+}
+
+// begin persists a solve that is about to start.
+func (jm *jobManager) begin(desc jobDescriptor) {
+	if jm.store == nil {
+		return
+	}
+	// Nothing drains the move channel of a recovered solve
+	desc.Options.StreamMoves = false
+	if err := jm.store.Put(desc.ID, desc); err != nil {
+		log.Printf("Persisting job %s: %v", desc.ID, err)
+	}
+}
+
+// end forgets a solve that reached a final status.
+func (jm *jobManager) end(id string) {
+	if jm.store == nil {
+		return
+	}
+	if err := jm.store.Delete(id); err != nil {
+		log.Printf("Removing job %s: %v", id, err)
+	}
+}
+
+// pending loads the descriptors left behind by a previous run. Unreadable
+// descriptors are logged and skipped.
+func (jm *jobManager) pending() ([]jobDescriptor, error) {
+	keys, err := jm.store.Keys()
+	if err != nil {
+		return nil, err
+	}
+	var jobs []jobDescriptor
+	for _, key := range keys {
+		var desc jobDescriptor
+		if err := jm.store.Get(key, &desc); err != nil || desc.ID != key {
+			log.Printf("Skipping unreadable job %s: %v", key, err)
+			continue
+		}
+		jobs = append(jobs, desc)
+	}
+	return jobs, nil
+}
+
+// EnableJobRecovery persists unfinished solves in dir. Solves left unfinished
+// by a previous run are registered again under their old IDs with status
+// "restarted" and re-run one after another in the background. They start
+// right away, so call it after the other Enable methods (EnableWorkers). A
+// solve resumed maxJobRestarts times is marked failed instead.
+func (s *Server) EnableJobRecovery(dir string) error {
+	st, err := store.Open(dir)
+	if err != nil {
+		return err
+	}
+	s.jobs.store = st
+
+	jobs, err := s.jobs.pending()
+	if err != nil {
+		return err
+	}
+	var resume []jobDescriptor
+	for _, desc := range jobs {
+		desc.Restarts++
+		s.tours.restore(desc)
+		if desc.Restarts > maxJobRestarts {
+			log.Printf("Giving up on job %s after %d restarts", desc.ID, maxJobRestarts)
+			s.tours.abandon(desc.ID, fmt.Sprintf("Gave up after %d restarts: the solve never finished, and it may be what stopped the server", maxJobRestarts))
+			s.jobs.end(desc.ID)
+			continue
+		}
+		s.jobs.begin(desc)
+		resume = append(resume, desc)
+	}
+	if len(resume) > 0 {
+		log.Printf("Re-queued %d unfinished solves from %s", len(resume), dir)
+	}
+
+	go func() {
+		for _, desc := range resume {
+			s.runJob(context.Background(), solver.NewSolver(), desc, "restart")
+		}
+	}()
+	return nil
+}
+
+// runJob runs a solve to its final status and records the outcome on its tour.
+// logID identifies the job's origin (a request ID) in log lines.
+func (s *Server) runJob(ctx context.Context, slv *solver.Solver, desc jobDescriptor, logID string) {
+	defer s.jobs.end(desc.ID)
+
+	jl := s.tours.jobLog(desc.ID)
+	jl.addf("[%s] Started with a %v timeout", logID, desc.timeout())
+	desc.Options.OnLog = jl.add
+
+	// The deadline travels with ctx into the solver goroutines; cancelling the
+	// solve (s.cancel) still stops it early.
+	solveCtx, cancelTimeout := context.WithTimeout(ctx, desc.timeout())
+	defer cancelTimeout()
+
+	solve := slv.SolveWithOptions
+	if desc.Distributed {
+		// Fails with distributed.ErrNoWorkers when the server was restarted without workers
+		solve = s.coordinator.Solve
+	}
+	result, err := solve(solveCtx, desc.Size, desc.StartPos, desc.Options)
+	// The tree is served separately; keep it out of the status payloads
+	if result != nil && result.Tree != nil {
+		s.tours.attachTree(desc.ID, result.Tree)
+		result.Tree = nil
+	}
+	switch {
+	case err == context.Canceled:
+		jl.addf("Cancelled before it finished")
+		s.tours.finish(desc.ID, statusCancelled, nil)
+	case err == context.DeadlineExceeded:
+		log.Printf("[%s] Solve %s timed out after %v", logID, desc.ID, desc.timeout())
+		jl.addf("Timed out after %v", desc.timeout())
+		s.tours.finish(desc.ID, statusTimeout, result)
+	case errors.Is(err, solver.ErrMemoryBudget):
+		log.Printf("[%s] Solve %s stopped: %v", logID, desc.ID, err)
+		var budgetErr *solver.MemoryBudgetError
+		errors.As(err, &budgetErr)
+		s.tours.exceedBudget(desc.ID, result, budgetErr)
+	case err != nil:
+		log.Printf("[%s] Solve %s error: %v", logID, desc.ID, err)
+		jl.addf("Failed: %v", err)
+		s.tours.finish(desc.ID, statusFailed, nil)
+		return
+	case result.Success:
+		s.tours.finish(desc.ID, statusSolved, result)
+	default:
+		s.tours.finish(desc.ID, statusFailed, result)
+	}
+
+	s.mu.Lock()
+	// A cancelled solve must not report over the one that replaced it
+	if result != nil && s.currentTour == desc.ID {
+		s.currentResult = result
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,37 @@
+package web
+
+import (
+	"log"
+	"net/http"
+
+	"the_knight/internal/gallery"
+)
+
+// EnableKiosk turns the server into a read-only gallery of the embedded tour
+// library, for exhibiting the project on hosts that cannot spare the CPU. The
+// index page shows the gallery and tours can still be listed, fetched and
+// rendered, but every endpoint that solves or adds tours answers 403.
+// Gallery tours use their library name as ID, e.g. /api/tours/8x8-closed.
+func (s *Server) EnableKiosk() error {
+	library, err := gallery.Load()
+	if err != nil {
+		return err
+	}
+	for _, t := range library {
+		s.tours.addRecording(t.Name, t.Recording)
+	}
+	s.kiosk = true
+	log.Printf("Kiosk mode: serving %d gallery tours, solving disabled", len(library))
+	return nil
+}
+
+// unlessKiosk refuses a request in kiosk mode and passes it to h otherwise.
+func (s *Server) unlessKiosk(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.kiosk {
+			http.Error(w, "Not available in kiosk mode", http.StatusForbidden)
+			return
+		}
+		h(w, r)
+	}
+}
@@ -0,0 +1,158 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// middleware wraps a handler with behaviour shared by every route.
+type middleware func(http.Handler) http.Handler
+
+// chain wraps h so that the first middleware is the outermost one.
+func chain(h http.Handler, mws ...middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// requestIDHeader carries the request ID in both directions.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs before they reach the logs.
+const maxRequestIDLength = 64
+
+type requestIDKey struct{}
+
+// requestID returns the ID assigned to the request by withRequestID, or "-".
+func requestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return "-"
+}
+
+// withRequestID tags every request with an ID, reusing a well-formed one sent
+// by the client (e.g. from a proxy) so logs can be correlated across hops.
+// The ID is echoed in the response headers.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// validRequestID accepts short IDs made of letters, digits, '-', '_' and '.'.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// withTiming logs every request with its status, size and duration once it completes.
+func withTiming(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		log.Printf("[%s] %s %s %d %dB %v", requestID(r.Context()), r.Method, r.URL.RequestURI(),
+			rec.statusCode(), rec.bytes, time.Since(started).Round(time.Microsecond))
+	})
+}
+
+// withRecovery turns a panicking handler into a 500 JSON response instead of
+// a dropped connection, and logs the panic with its stack trace. A handler
+// that panics after it started writing can only have its connection dropped.
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec, ok := w.(*statusRecorder)
+		if !ok {
+			rec = &statusRecorder{ResponseWriter: w}
+		}
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				// Deliberate abort; let net/http handle it quietly
+				panic(p)
+			}
+			id := requestID(r.Context())
+			log.Printf("[%s] panic serving %s %s: %v\n%s", id, r.Method, r.URL.Path, p, debug.Stack())
+			if rec.status != 0 {
+				// Part of the response is already out: abort so net/http
+				// drops the connection instead of ending a truncated 200
+				// that looks complete
+				panic(http.ErrAbortHandler)
+			}
+			rec.Header().Set("Content-Type", "application/json")
+			rec.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(rec).Encode(map[string]string{"error": "internal server error", "requestId": id})
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// statusRecorder remembers the status code and body size of a response.
+// It keeps http.Flusher working for the SSE handlers.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(p)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (rec *statusRecorder) Flush() {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// statusCode returns the status sent, treating a response without one as 200.
+func (rec *statusRecorder) statusCode() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
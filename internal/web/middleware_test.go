@@ -0,0 +1,36 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoveryAbortsPartialResponse(t *testing.T) {
+	ts := httptest.NewServer(withRecovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"moves": [`))
+		w.(http.Flusher).Flush()
+		panic("boom")
+	})))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		return // dropped before the headers arrived
+	}
+	defer resp.Body.Close()
+	if body, err := io.ReadAll(resp.Body); err == nil {
+		t.Errorf("truncated response read as complete: %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestRecoveryReports500BeforeWriting(t *testing.T) {
+	rec := httptest.NewRecorder()
+	withRecovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
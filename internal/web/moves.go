@@ -0,0 +1,86 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// movesChunk is the number of moves written between flushes of a moves response.
+const movesChunk = 1000
+
+// handleMoves streams the move updates of a tour as a JSON array, encoding
+// one move at a time so a 200x200 tour never sits in memory as one document.
+// offset and limit select a window of the updates; the response carries the
+// window and the total in a Content-Range header ("moves 0-999/40000") and
+// the total in X-Total-Count, so clients can page through long runs.
+// GET /api/tours/{id}/moves?offset=0&limit=1000&coordinates=chess
+func (s *Server) handleMoves(w http.ResponseWriter, r *http.Request, tour tourRecord) {
+	if tour.Result == nil {
+		if tour.Status == statusSolving || tour.Status == statusRestarted {
+			http.Error(w, "Tour is still solving", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Tour has no moves", http.StatusNotFound)
+		return
+	}
+	coordinates, err := coordinatesParam(r, tour.Coordinates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	moves := tour.Result.Moves
+	query := r.URL.Query()
+	offset, limit := 0, len(moves)
+	for name, dst := range map[string]*int{"offset": &offset, "limit": &limit} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, fmt.Sprintf("%s must be a non-negative integer", name), http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+	offset = min(offset, len(moves))
+	limit = min(limit, len(moves)-offset)
+	window := moves[offset : offset+limit]
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(moves)))
+	if len(window) > 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("moves %d-%d/%d", offset, offset+len(window)-1, len(moves)))
+	} else {
+		w.Header().Set("Content-Range", fmt.Sprintf("moves */%d", len(moves)))
+	}
+
+	flusher, _ := w.(http.Flusher)
+	buf := bufio.NewWriter(w)
+	rows := tour.rows()
+
+	buf.WriteString("[")
+	for i, move := range window {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		data, err := json.Marshal(moveView(move, coordinates, rows))
+		if err != nil {
+			return
+		}
+		buf.Write(data)
+		if (i+1)%movesChunk == 0 {
+			// Stop early once the client has gone away
+			if buf.Flush() != nil || r.Context().Err() != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+	buf.WriteString("]\n")
+	buf.Flush()
+}
@@ -0,0 +1,196 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// Race limits. Brute force rarely finishes on larger boards, so every race is bounded.
+const (
+	defaultRaceTimeout = 10 * time.Second
+	maxRaceTimeout     = 60 * time.Second
+	raceTickInterval   = 100 * time.Millisecond
+)
+
+// raceResult is the outcome of one algorithm in a race.
+type raceResult struct {
+	Algorithm  string `json:"algorithm"`
+	Success    bool   `json:"success"`
+	TimedOut   bool   `json:"timedOut"`
+	Attempts   int    `json:"attempts"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// handleRace runs two algorithms on the same board concurrently and streams
+// their progress as Server-Sent Events, ending with a comparison summary.
+//
+// Events: {"type":"progress","algorithm":...,"attempts":...,"depth":...}
+// for every running algorithm each tick, {"type":"finished",...} once per
+// algorithm and a final {"type":"summary","winner":...,"results":[...]}.
+func (s *Server) handleRace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Size        int             `json:"size"`
+		StartPos    json.RawMessage `json:"startPos"`
+		Coordinates string          `json:"coordinates"`
+		Algorithms  []string        `json:"algorithms"`
+		TimeoutMs   int             `json:"timeoutMs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.Size == 0 {
+		req.Size = 8 // Default to 8x8
+	}
+	if req.Size < 0 || req.Size > maxSolveSize {
+		http.Error(w, fmt.Sprintf("size must be between 1 and %d", maxSolveSize), http.StatusBadRequest)
+		return
+	}
+	if !board.IsValidCoordinates(req.Coordinates) {
+		http.Error(w, fmt.Sprintf("Unknown coordinates %q", req.Coordinates), http.StatusBadRequest)
+		return
+	}
+	startPos, err := decodePosition(req.StartPos, req.Coordinates, req.Size)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid start position: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !board.NewBoard(req.Size).Contains(startPos) {
+		http.Error(w, "Start position is off the board", http.StatusBadRequest)
+		return
+	}
+	if len(req.Algorithms) == 0 {
+		req.Algorithms = []string{solver.AlgorithmWarnsdorff, solver.AlgorithmBacktracking}
+	}
+	if len(req.Algorithms) != 2 {
+		http.Error(w, "A race needs exactly two algorithms", http.StatusBadRequest)
+		return
+	}
+	for _, name := range req.Algorithms {
+		if name == "" || !solver.IsValidAlgorithm(name) {
+			http.Error(w, fmt.Sprintf("Unknown algorithm %q", name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	timeout := defaultRaceTimeout
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	if timeout > maxRaceTimeout {
+		timeout = maxRaceTimeout
+	}
+
+	// Both racers stop when the race times out or the client goes away
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	flusher, _ := w.(http.Flusher)
+	send := func(event any) {
+		data, _ := json.Marshal(event)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	solvers := make([]*solver.Solver, len(req.Algorithms))
+	results := make([]*raceResult, len(req.Algorithms))
+	done := make(chan int, len(req.Algorithms))
+	// finished is only touched by this goroutine; results[i] is safe to read once i arrives on done
+	finished := make([]bool, len(req.Algorithms))
+
+	for i, name := range req.Algorithms {
+		solvers[i] = solver.NewSolver()
+		go func(i int, name string) {
+			started := time.Now()
+			result, err := solvers[i].SolveWithOptions(ctx, req.Size, startPos, solver.SolveOptions{Algorithm: name})
+
+			res := &raceResult{
+				Algorithm:  name,
+				Success:    result != nil && result.Success,
+				TimedOut:   err == context.DeadlineExceeded,
+				DurationMs: time.Since(started).Milliseconds(),
+			}
+			if result != nil {
+				res.Attempts = result.AttemptCount
+			}
+			if err != nil && !res.TimedOut {
+				res.Error = err.Error()
+			}
+			results[i] = res
+			done <- i
+		}(i, name)
+	}
+
+	ticker := time.NewTicker(raceTickInterval)
+	defer ticker.Stop()
+
+	for remaining := len(solvers); remaining > 0; {
+		select {
+		case i := <-done:
+			remaining--
+			finished[i] = true
+			send(struct {
+				Type string `json:"type"`
+				*raceResult
+			}{"finished", results[i]})
+
+		case <-ticker.C:
+			for i, slv := range solvers {
+				if finished[i] {
+					continue
+				}
+				attempts, depth := slv.Progress()
+				send(map[string]any{
+					"type":      "progress",
+					"algorithm": req.Algorithms[i],
+					"attempts":  attempts,
+					"depth":     depth,
+				})
+			}
+
+		case <-r.Context().Done():
+			// Client disconnected; the racers stop via ctx
+			return
+		}
+	}
+
+	send(map[string]any{
+		"type":    "summary",
+		"winner":  raceWinner(results),
+		"results": results,
+	})
+}
+
+// raceWinner returns the fastest successful algorithm, or "" if none succeeded.
+func raceWinner(results []*raceResult) string {
+	var winner *raceResult
+	for _, res := range results {
+		if res.Success && (winner == nil || res.DurationMs < winner.DurationMs) {
+			winner = res
+		}
+	}
+	if winner == nil {
+		return ""
+	}
+	return winner.Algorithm
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"the_knight/internal/recording"
+)
+
+// maxRecordingSize caps uploads; long brute-force runs produce large files.
+const maxRecordingSize = 64 << 20
+
+// handleUploadRecording accepts a .ktr file and registers it as a tour so it
+// can be shared, inspected and rendered like any other.
+// POST /api/recordings (body: the .ktr file)
+func (s *Server) handleUploadRecording(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rec, err := recording.Read(http.MaxBytesReader(w, r.Body, maxRecordingSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid recording: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	tour := s.tours.createFromRecording(rec)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":      tour.ID,
+		"status":  tour.Status,
+		"updates": len(rec.Updates),
+	})
+}
+
+// handleDownloadRecording serves the original run of an uploaded tour.
+// GET /api/tours/{id}/recording.ktr
+func (s *Server) handleDownloadRecording(w http.ResponseWriter, r *http.Request, tour tourRecord) {
+	if tour.recording == nil {
+		http.Error(w, "Tour has no recording", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", tour.ID+recording.FileExtension))
+	if err := tour.recording.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -7,11 +7,16 @@ import (
 	"html/template"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"the_knight/internal/distributed"
+	"the_knight/internal/i18n"
 	"the_knight/internal/solver"
 	"the_knight/pkg/board"
+	assets "the_knight/web"
 )
 
 // Server handles HTTP requests and manages the solver state.
@@ -19,19 +24,58 @@ type Server struct {
 	solver        *solver.Solver
 	mu            sync.RWMutex
 	currentResult *solver.SolveResult
+	currentTour   string
+	tours         *tourStore
+	jobs          *jobManager
 	templates     *template.Template
 	ctx           context.Context
 	cancel        context.CancelFunc
+	// kiosk serves the gallery read-only (see EnableKiosk)
+	kiosk bool
+	// coordinator runs distributed solves (see EnableWorkers)
+	coordinator *distributed.Coordinator
+	// workerToken and workerSlots admit coordinators' tasks (see EnableWorkerMode)
+	workerToken string
+	workerSlots chan struct{}
 }
 
+// Solve timeouts. Every solve runs under a deadline so an abandoned search
+// cannot hold the CPU forever; clients may ask for a shorter one.
+const (
+	defaultSolveTimeout = 2 * time.Minute
+	maxSolveTimeout     = 10 * time.Minute
+)
+
+// Memory budgets, in bytes, of a single solve. Recording a large search tree
+// is the main consumer; clients may ask for a smaller budget.
+const (
+	defaultMemoryBudget = 64 << 20
+	maxMemoryBudget     = 256 << 20
+)
+
+// maxTreeNodeCap bounds the search tree a client may ask the server to keep.
+const maxTreeNodeCap = 200000
+
+// maxSolveSize bounds the size of square boards; larger sizes fall back to 8x8.
+const maxSolveSize = solver.MaxBoardSize
+
+// streamIdleTimeout closes move streams that have not sent a move for this
+// long. It is a variable so tests can shorten it.
+var streamIdleTimeout = 30 * time.Second
+
+// maxCompositeSide bounds the bounding box of composite boards, in squares.
+const maxCompositeSide = 60
+
 // NewServer creates a new web server instance.
 func NewServer() *Server {
-	tmpl := template.Must(template.ParseGlob("web/templates/*.html"))
+	tmpl := template.Must(template.ParseFS(assets.Templates, "templates/*.html"))
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Server{
 		solver:    solver.NewSolver(),
+		tours:     newTourStore(),
+		jobs:      &jobManager{},
 		templates: tmpl,
 		ctx:       ctx,
 		cancel:    cancel,
@@ -40,23 +84,57 @@ func NewServer() *Server {
 
 // Start begins the HTTP server on the specified address.
 func (s *Server) Start(addr string) error {
+	log.Printf("Server starting on %s", addr)
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// Handler returns the server's routes wrapped in the middleware chain.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
 	// Serve static files if needed
 	fs := http.FileServer(http.Dir("web/static"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
+	mux.Handle("/static/", http.StripPrefix("/static/", fs))
 
 	// Routes
-	http.HandleFunc("/", s.handleIndex)
-	http.HandleFunc("/api/solve", s.handleSolve)
-	http.HandleFunc("/api/moves/stream", s.handleMoveStream)
-	http.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/api/solve", s.unlessKiosk(s.handleSolve))
+	mux.HandleFunc("/api/moves/stream", s.handleMoveStream)
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/race", s.unlessKiosk(s.handleRace))
+	mux.HandleFunc("/api/tours", s.handleTours)
+	mux.HandleFunc("/api/tours/", s.handleTour)
+	mux.HandleFunc("/api/analysis/heat", s.unlessKiosk(s.handleHeat))
+	mux.HandleFunc("/api/estimate", s.unlessKiosk(s.handleEstimate))
+	mux.HandleFunc("/api/recordings", s.unlessKiosk(s.handleUploadRecording))
+	mux.HandleFunc("/api/verify", s.handleVerify)
+	mux.HandleFunc(distributed.WorkerPath, s.unlessKiosk(s.handleWorkerSolve))
 
-	log.Printf("Server starting on %s", addr)
-	return http.ListenAndServe(addr, nil)
+	// /api/v1/... is the versioned spelling of every /api/... route
+	mux.HandleFunc("/api/v1/", func(w http.ResponseWriter, r *http.Request) {
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/api/" + strings.TrimPrefix(r.URL.Path, "/api/v1/")
+		r2.URL.RawPath = ""
+		mux.ServeHTTP(w, r2)
+	})
+
+	return chain(mux, withRequestID, withTiming, withRecovery)
 }
 
-// handleIndex serves the main HTML page with HTMX.
+// handleIndex serves the main HTML page with HTMX, or the gallery in kiosk mode,
+// in the language of ?lang= or else the one negotiated from Accept-Language.
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
-	if err := s.templates.ExecuteTemplate(w, "index.html", nil); err != nil {
+	page := "index.html"
+	if s.kiosk {
+		page = "gallery.html"
+	}
+	lang := i18n.Match(r.URL.Query().Get("lang"))
+	if lang == "" {
+		lang = i18n.Negotiate(r.Header.Get("Accept-Language"))
+	}
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	if err := s.templates.ExecuteTemplate(w, page, i18n.New(lang)); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -68,51 +146,142 @@ func (s *Server) handleSolve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Cancel any existing solve and create new solver instance
+	// Parse request
+	var req struct {
+		Size int `json:"size"`
+		// StartPos is {"X": row, "Y": column}, or a square name like "a8"
+		// when Coordinates is "chess"
+		StartPos    json.RawMessage `json:"startPos"`
+		Coordinates string          `json:"coordinates"`
+		TimeoutMs   int             `json:"timeoutMs"`
+		// RecordTree keeps the search tree for /api/tours/{id}/tree
+		RecordTree  bool `json:"recordTree"`
+		TreeNodeCap int  `json:"treeNodeCap"`
+		// MemoryBudget caps the memory of the solve, in bytes (0 = server default)
+		MemoryBudget int64 `json:"memoryBudget"`
+		// DebugLog also captures the solver's debug lines in /api/tours/{id}/logs
+		DebugLog bool `json:"debugLog"`
+		// Stream (default true) feeds /api/moves/stream. Clients that only
+		// fetch the result set it to false; otherwise a solve of more than
+		// 1000 updates waits for a stream reader.
+		Stream *bool `json:"stream"`
+		// Shape ("L" or "plus") builds a composite board of size x size blocks;
+		// Rects describes one explicitly. Both replace the square board.
+		Shape string       `json:"shape"`
+		Rects []board.Rect `json:"rects"`
+		// Distributed searches on the workers of a coordinator (see EnableWorkers)
+		Distributed bool `json:"distributed"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.Size <= 0 || req.Size > maxSolveSize {
+		req.Size = 8 // Default to 8x8
+	}
+
+	if !board.IsValidCoordinates(req.Coordinates) {
+		http.Error(w, fmt.Sprintf("Unknown coordinates %q", req.Coordinates), http.StatusBadRequest)
+		return
+	}
+	if req.Coordinates == "" {
+		req.Coordinates = board.CoordinatesMatrix
+	}
+
+	if req.TreeNodeCap < 0 || req.TreeNodeCap > maxTreeNodeCap {
+		http.Error(w, fmt.Sprintf("treeNodeCap must be between 0 and %d", maxTreeNodeCap), http.StatusBadRequest)
+		return
+	}
+
+	if req.Distributed && s.coordinator == nil {
+		http.Error(w, "No workers configured for distributed solves", http.StatusBadRequest)
+		return
+	}
+	if req.Distributed && req.RecordTree {
+		http.Error(w, "recordTree is not supported for distributed solves", http.StatusBadRequest)
+		return
+	}
+
+	if req.MemoryBudget < 0 || req.MemoryBudget > maxMemoryBudget {
+		http.Error(w, fmt.Sprintf("memoryBudget must be between 0 and %d bytes", maxMemoryBudget), http.StatusBadRequest)
+		return
+	}
+	if req.MemoryBudget == 0 {
+		req.MemoryBudget = defaultMemoryBudget
+	}
+
+	rects, err := compositeRects(req.Shape, req.Size, req.Rects)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid board shape: %v", err), http.StatusBadRequest)
+		return
+	}
+	opts := solver.SolveOptions{
+		StreamMoves:  req.Stream == nil || *req.Stream,
+		RecordTree:   req.RecordTree,
+		TreeNodeCap:  req.TreeNodeCap,
+		MemoryBudget: req.MemoryBudget,
+		LogDebug:     req.DebugLog,
+		Shape:        rects,
+	}
+	b, err := opts.Board(req.Size)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid board shape: %v", err), http.StatusBadRequest)
+		return
+	}
+	rows, _ := b.Dimensions()
+	startPos, err := decodePosition(req.StartPos, req.Coordinates, rows)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid start position: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !b.Contains(startPos) {
+		http.Error(w, "Start position is not on the board", http.StatusBadRequest)
+		return
+	}
+
+	timeout := defaultSolveTimeout
+	if req.TimeoutMs > 0 {
+		timeout = time.Duration(req.TimeoutMs) * time.Millisecond
+	}
+	if timeout > maxSolveTimeout {
+		timeout = maxSolveTimeout
+	}
+
+	// Only a valid request replaces the current solve: cancel it and create
+	// a new solver instance to reset state
+	tour := s.tours.create(req.Size, rects, startPos, req.Coordinates)
 	s.mu.Lock()
 	if s.cancel != nil {
 		s.cancel()
 	}
-	// Create new solver instance to reset state
 	s.solver = solver.NewSolver()
+	slv := s.solver
 	ctx, cancel := context.WithCancel(context.Background())
 	s.ctx = ctx
 	s.cancel = cancel
 	s.currentResult = nil
+	s.currentTour = tour.ID
 	s.mu.Unlock()
 
-	// Parse request
-	var req struct {
-		Size     int            `json:"size"`
-		StartPos board.Position `json:"startPos"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
-		return
-	}
-
-	if req.Size <= 0 || req.Size > 20 {
-		req.Size = 8 // Default to 8x8
+	desc := jobDescriptor{
+		ID:          tour.ID,
+		Size:        req.Size,
+		StartPos:    startPos,
+		Options:     opts,
+		TimeoutMs:   timeout.Milliseconds(),
+		CreatedAt:   tour.CreatedAt,
+		Coordinates: req.Coordinates,
+		Distributed: req.Distributed,
 	}
+	s.jobs.begin(desc)
 
 	// Start solving in background
-	go func() {
-		result, err := s.solver.Solve(ctx, req.Size, req.StartPos)
-		if err != nil && err != context.Canceled {
-			log.Printf("Solve error: %v", err)
-			return
-		}
-
-		s.mu.Lock()
-		if result != nil {
-			s.currentResult = result
-		}
-		s.mu.Unlock()
-	}()
+	go s.runJob(ctx, slv, desc, requestID(r.Context()))
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "solving"})
+	json.NewEncoder(w).Encode(map[string]string{"status": statusSolving, "id": tour.ID})
 }
 
 // handleMoveStream streams moves via Server-Sent Events (SSE) for HTMX.
@@ -123,6 +292,17 @@ func (s *Server) handleMoveStream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
+	// Moves are reported in the convention of the current solve unless the client asks otherwise
+	s.mu.RLock()
+	current, _ := s.tours.get(s.currentTour)
+	s.mu.RUnlock()
+	coordinates, err := coordinatesParam(r, current.Coordinates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rows := current.rows()
+
 	// Get move channel
 	moveChan := s.solver.GetMoveChannel()
 
@@ -131,39 +311,63 @@ func (s *Server) handleMoveStream(w http.ResponseWriter, r *http.Request) {
 		flusher.Flush()
 	}
 
+	// complete sends the completion event once the current solve has a result.
+	complete := func() bool {
+		s.mu.RLock()
+		result := s.currentResult
+		tourID := s.currentTour
+		s.mu.RUnlock()
+
+		if result == nil {
+			return false
+		}
+		timedOut := false
+		if tour, ok := s.tours.get(tourID); ok {
+			timedOut = tour.Status == statusTimeout
+		}
+		fmt.Fprintf(w, "data: {\"type\":\"complete\",\"success\":%t,\"timeout\":%t}\n\n", result.Success, timedOut)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	// A timed out search stops producing moves, so completion is also polled
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	// Connections that receive no move for streamIdleTimeout are closed
+	idle := time.NewTimer(streamIdleTimeout)
+	defer idle.Stop()
+
 	// Stream moves
 	for {
 		select {
 		case move := <-moveChan:
 			// Send as HTMX SSE format
-			data, _ := json.Marshal(move)
+			data, _ := json.Marshal(moveView(move, coordinates, rows))
 			fmt.Fprintf(w, "data: %s\n\n", string(data))
 
 			if flusher, ok := w.(http.Flusher); ok {
 				flusher.Flush()
 			}
+			if !idle.Stop() {
+				<-idle.C
+			}
+			idle.Reset(streamIdleTimeout)
 
 			// Check if we should stop (solution found or failed)
-			s.mu.RLock()
-			result := s.currentResult
-			s.mu.RUnlock()
-
-			if result != nil {
-				if result.Success {
-					// Send completion event
-					fmt.Fprintf(w, "data: {\"type\":\"complete\",\"success\":true}\n\n")
-				} else {
-					fmt.Fprintf(w, "data: {\"type\":\"complete\",\"success\":false}\n\n")
-				}
-				if flusher, ok := w.(http.Flusher); ok {
-					flusher.Flush()
-				}
+			if complete() {
+				return
+			}
+
+		case <-ticker.C:
+			if len(moveChan) == 0 && complete() {
 				return
 			}
 
 		case <-r.Context().Done():
 			return
-		case <-time.After(30 * time.Second):
+		case <-idle.C:
 			// Timeout to prevent hanging connections
 			return
 		}
@@ -174,12 +378,133 @@ func (s *Server) handleMoveStream(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	s.mu.RLock()
 	result := s.currentResult
+	tourID := s.currentTour
 	s.mu.RUnlock()
 
+	tour, ok := s.tours.get(tourID)
+	coordinates, err := coordinatesParam(r, tour.Coordinates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	if ok && tour.Status == statusTimeout {
+		attempts := 0
+		if tour.Result != nil {
+			attempts = tour.Result.AttemptCount
+		}
+		w.WriteHeader(http.StatusRequestTimeout)
+		json.NewEncoder(w).Encode(map[string]any{"status": statusTimeout, "attemptCount": attempts})
+		return
+	}
 	if result != nil {
-		json.NewEncoder(w).Encode(result)
+		json.NewEncoder(w).Encode(resultView(result, coordinates, tour.rows()))
 	} else {
 		json.NewEncoder(w).Encode(map[string]string{"status": "not_started"})
 	}
 }
+
+// handleTours lists tours, e.g. the prettiest solved 8x8 tours:
+// GET /api/tours?status=solved&size=8&sort=crossings&limit=10
+func (s *Server) handleTours(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	q := tourQuery{Status: query.Get("status"), SortBy: query.Get("sort")}
+	if _, ok := tourSorts[q.SortBy]; q.SortBy != "" && !ok {
+		http.Error(w, fmt.Sprintf("Unknown sort %q", q.SortBy), http.StatusBadRequest)
+		return
+	}
+	for name, dst := range map[string]*int{"size": &q.Size, "limit": &q.Limit} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, fmt.Sprintf("Invalid %s: %q", name, v), http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+
+	coordinates, err := coordinatesParam(r, board.CoordinatesMatrix)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summariesView(s.tours.list(q), coordinates))
+}
+
+// handleTour dispatches requests under /api/tours/{id}.
+func (s *Server) handleTour(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/tours/"), "/")
+	tour, ok := s.tours.get(parts[0])
+	if !ok {
+		http.Error(w, "Tour not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		coordinates, err := coordinatesParam(r, tour.Coordinates)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if tour.Status == statusTimeout {
+			w.WriteHeader(http.StatusRequestTimeout)
+		}
+		json.NewEncoder(w).Encode(tourView(tour, coordinates))
+	case len(parts) == 3 && parts[1] == "frame":
+		s.handleFrame(w, r, tour, parts[2])
+	case len(parts) == 2 && parts[1] == "logs":
+		s.handleLogs(w, r, tour)
+	case len(parts) == 2 && parts[1] == "moves":
+		s.handleMoves(w, r, tour)
+	case len(parts) == 2 && parts[1] == "tree":
+		s.handleTree(w, r, tour)
+	case len(parts) == 2 && parts[1] == "recording.ktr":
+		s.handleDownloadRecording(w, r, tour)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// compositeRects resolves the board shape of a solve request: a named shape
+// of size x size blocks, explicit rectangles, or nil for a square board.
+func compositeRects(shape string, size int, rects []board.Rect) ([]board.Rect, error) {
+	switch {
+	case shape != "" && len(rects) > 0:
+		return nil, fmt.Errorf("shape and rects are mutually exclusive")
+	case shape != "":
+		var err error
+		if rects, err = board.ShapeRects(shape, size); err != nil {
+			return nil, err
+		}
+	}
+	return rects, checkCompositeSide(rects)
+}
+
+// checkCompositeSide rejects rectangles reaching past maxCompositeSide, before
+// a board is allocated for them.
+func checkCompositeSide(rects []board.Rect) error {
+	for _, r := range rects {
+		// Compared one by one so the sums cannot overflow
+		if r.X > maxCompositeSide || r.Rows > maxCompositeSide || r.X+r.Rows > maxCompositeSide ||
+			r.Y > maxCompositeSide || r.Cols > maxCompositeSide || r.Y+r.Cols > maxCompositeSide {
+			return fmt.Errorf("composite boards are limited to %dx%d", maxCompositeSide, maxCompositeSide)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+func TestTimeoutStatusMapping(t *testing.T) {
+	s := NewServer()
+	tour := s.tours.create(8, nil, board.Position{}, board.CoordinatesMatrix)
+	s.tours.finish(tour.ID, statusTimeout, &solver.SolveResult{AttemptCount: 42})
+	s.currentTour = tour.ID
+	h := s.Handler()
+
+	for _, path := range []string{"/api/status", "/api/tours/" + tour.ID} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusRequestTimeout {
+			t.Errorf("GET %s: status %d, want %d", path, rec.Code, http.StatusRequestTimeout)
+		}
+		var body struct {
+			Status string `json:"status"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Status != statusTimeout {
+			t.Errorf("GET %s: body status %q (%v), want %q", path, body.Status, err, statusTimeout)
+		}
+	}
+}
+
+func TestSolveTimeoutReports408(t *testing.T) {
+	s := NewServer()
+	h := s.Handler()
+
+	// 5x5 from (0, 1) has no tour; the exhaustive search outlasts 1ms
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/solve",
+		strings.NewReader(`{"size": 5, "startPos": {"X": 0, "Y": 1}, "timeoutMs": 1, "stream": false}`)))
+	var started struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&started); err != nil || started.ID == "" {
+		t.Fatalf("POST /api/solve: %d %v", rec.Code, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/tours/"+started.ID, nil))
+		if rec.Code == http.StatusRequestTimeout {
+			return
+		}
+		if !strings.Contains(rec.Body.String(), `"status":"solving"`) || time.Now().After(deadline) {
+			t.Fatalf("GET /api/tours/%s: %d %s, want 408", started.ID, rec.Code, rec.Body.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestInvalidSolveKeepsCurrentSolve(t *testing.T) {
+	s := NewServer()
+	tour := s.tours.create(8, nil, board.Position{}, board.CoordinatesMatrix)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.ctx, s.cancel, s.currentTour = ctx, cancel, tour.ID
+	slv := s.solver
+	h := s.Handler()
+
+	for _, body := range []string{
+		`{"size": 8, "startPos": `,
+		`{"size": 8, "startPos": {"X": 0, "Y": 0}, "coordinates": "klingon"}`,
+		`{"size": 8, "startPos": {"X": 9, "Y": 0}}`,
+		`{"size": 8, "startPos": {"X": 0, "Y": 0}, "memoryBudget": -1}`,
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/solve", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: status %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+	if ctx.Err() != nil || s.solver != slv || s.currentTour != tour.ID {
+		t.Errorf("invalid requests replaced the current solve")
+	}
+}
+
+func TestOversizedRectsRejectedBeforeAllocating(t *testing.T) {
+	h := NewServer().Handler()
+	// 1<<32 squared overflows to 0 cells
+	rects := `"rects": [{"x": 0, "y": 0, "rows": 4294967296, "cols": 4294967296}]`
+	for _, path := range []string{"/api/solve", "/api/estimate"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path,
+			strings.NewReader(`{"size": 8, "startPos": {"X": 0, "Y": 0}, `+rects+`}`)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: status %d, want %d", path, rec.Code, http.StatusBadRequest)
+		}
+	}
+	if _, err := board.NewComposite([]board.Rect{{Rows: 1 << 32, Cols: 1 << 32}}); err == nil {
+		t.Error("NewComposite accepted a 1<<32 x 1<<32 rectangle")
+	}
+}
+
+func TestIdleMoveStreamCloses(t *testing.T) {
+	// Longer than the 500ms completion poll, which must not restart the timeout
+	defer func(d time.Duration) { streamIdleTimeout = d }(streamIdleTimeout)
+	streamIdleTimeout = 700 * time.Millisecond
+	h := NewServer().Handler()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/moves/stream", nil))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle move stream still open after 5s")
+	}
+}
+
+func TestRaceRejectsOutOfRangeSize(t *testing.T) {
+	h := NewServer().Handler()
+	for _, size := range []int{-1, maxSolveSize + 1} {
+		rec := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"size": %d, "startPos": {"X": 0, "Y": 0}}`, size)
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/race", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("size %d: status %d, want %d", size, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestConfigureRejectsKioskWithSolving(t *testing.T) {
+	for _, cfg := range []Config{
+		{Kiosk: true, Workers: []string{"http://10.0.0.2:8080"}, WorkerToken: "t"},
+		{Kiosk: true, Worker: true, WorkerToken: "t", WorkerSlots: 1},
+		{Kiosk: true, DataDir: t.TempDir()},
+	} {
+		if err := NewServer().Configure(cfg); err == nil {
+			t.Errorf("Configure(%+v) accepted a kiosk that solves", cfg)
+		}
+	}
+}
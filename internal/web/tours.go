@@ -0,0 +1,333 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"the_knight/internal/recording"
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+	"the_knight/pkg/tour"
+)
+
+// Tour statuses reported by the API.
+const (
+	statusSolving   = "solving"
+	statusSolved    = "solved"
+	statusFailed    = "failed"
+	statusCancelled = "cancelled"
+	statusTimeout   = "timeout"
+	// statusRestarted marks a solve interrupted by a server restart that has
+	// been queued again and has not finished yet.
+	statusRestarted = "restarted"
+)
+
+// tourRecord is a solve tracked by ID so finished tours can be revisited.
+type tourRecord struct {
+	ID   string `json:"id"`
+	Size int    `json:"size"` // 0 for composite boards
+	// Shape holds the rectangles of a composite board
+	Shape     []board.Rect        `json:"shape,omitempty"`
+	StartPos  board.Position      `json:"startPos"`
+	Status    string              `json:"status"`
+	Result    *solver.SolveResult `json:"result,omitempty"`
+	Metrics   *tour.Metrics       `json:"metrics,omitempty"`
+	CreatedAt time.Time           `json:"createdAt"`
+	// Restarts counts the server restarts the solve survived
+	Restarts int `json:"restarts,omitempty"`
+	// Coordinates is the convention positions are reported in; it defaults
+	// to the one the solve was requested in
+	Coordinates string `json:"coordinates,omitempty"`
+	// MemoryBudget explains a solve stopped by its memory budget
+	MemoryBudget *solver.MemoryBudgetError `json:"memoryBudget,omitempty"`
+	// Error explains a solve that failed without running, e.g. one given up
+	// after too many restarts
+	Error string `json:"error,omitempty"`
+	// recording is set for tours uploaded as .ktr files
+	recording *recording.Recording
+	// tree is the explored search tree of solves started with recordTree
+	tree *solver.SearchTree
+	// log captures the log lines of solves run on this server
+	log *jobLog
+}
+
+// path returns the squares of a solved tour in move order.
+func (t *tourRecord) path() []board.Position {
+	if t.Result == nil {
+		return nil
+	}
+	path := make([]board.Position, 0, len(t.Result.Moves))
+	for _, move := range t.Result.Moves {
+		if !move.IsBacktrack {
+			path = append(path, move.Position)
+		}
+	}
+	return path
+}
+
+// board returns an empty board of the tour's shape.
+func (t *tourRecord) board() board.Board {
+	if len(t.Shape) > 0 {
+		// The shape was validated when the tour was created
+		b, _ := board.NewComposite(t.Shape)
+		return b
+	}
+	return board.NewBoard(t.Size)
+}
+
+// rows returns the number of rows of the tour's board (its bounding box for composite boards).
+func (t *tourRecord) rows() int {
+	if len(t.Shape) == 0 {
+		return t.Size
+	}
+	rows := 0
+	for _, r := range t.Shape {
+		rows = max(rows, r.X+r.Rows)
+	}
+	return rows
+}
+
+// computeMetrics measures a solved tour for the gallery queries.
+func (t *tourRecord) computeMetrics() {
+	rows, cols := t.board().Dimensions()
+	metrics := tour.ComputeMetrics(rows, cols, t.path())
+	t.Metrics = &metrics
+}
+
+// tourSummary is a tour without its moves, as returned by listings.
+type tourSummary struct {
+	ID        string         `json:"id"`
+	Size      int            `json:"size"`
+	Composite bool           `json:"composite,omitempty"`
+	StartPos  board.Position `json:"startPos"`
+	Status    string         `json:"status"`
+	Metrics   *tour.Metrics  `json:"metrics,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+	// rows converts StartPos to chess coordinates
+	rows int
+}
+
+// tourQuery filters and orders a tour listing.
+type tourQuery struct {
+	Status string // only tours in this status ("" = any)
+	Size   int    // only tours of this size (0 = any)
+	SortBy string // one of the tourSorts keys ("" = newest first)
+	Limit  int    // maximum number of results (0 = no limit)
+}
+
+// tourSorts defines the orderings available to listings; each reports whether a ranks before b.
+// Unsolved tours have no metrics and always sort last.
+var tourSorts = map[string]func(a, b *tour.Metrics) bool{
+	"crossings":  func(a, b *tour.Metrics) bool { return a.Crossings < b.Crossings },
+	"-crossings": func(a, b *tour.Metrics) bool { return a.Crossings > b.Crossings },
+	"symmetry":   func(a, b *tour.Metrics) bool { return a.SymmetryScore > b.SymmetryScore },
+	"length":     func(a, b *tour.Metrics) bool { return a.PathLength < b.PathLength },
+}
+
+// tourStore keeps every tour started on this server in memory.
+type tourStore struct {
+	mu    sync.RWMutex
+	tours map[string]*tourRecord
+}
+
+func newTourStore() *tourStore {
+	return &tourStore{tours: make(map[string]*tourRecord)}
+}
+
+// create registers a new tour in the solving state and returns it.
+// A non-empty shape makes it a tour of a composite board.
+func (ts *tourStore) create(size int, shape []board.Rect, startPos board.Position, coordinates string) *tourRecord {
+	if len(shape) > 0 {
+		size = 0
+	}
+	tour := &tourRecord{
+		ID:          newID(),
+		Size:        size,
+		Shape:       shape,
+		StartPos:    startPos,
+		Status:      statusSolving,
+		CreatedAt:   time.Now(),
+		Coordinates: coordinates,
+		log:         &jobLog{},
+	}
+
+	ts.mu.Lock()
+	ts.tours[tour.ID] = tour
+	ts.mu.Unlock()
+	return tour
+}
+
+// restore registers a persisted solve again under its original ID.
+func (ts *tourStore) restore(desc jobDescriptor) {
+	tour := &tourRecord{
+		ID:          desc.ID,
+		Size:        desc.Size,
+		Shape:       desc.Options.Shape,
+		StartPos:    desc.StartPos,
+		Status:      statusRestarted,
+		CreatedAt:   desc.CreatedAt,
+		Restarts:    desc.Restarts,
+		Coordinates: desc.Coordinates,
+		log:         &jobLog{},
+	}
+	if len(tour.Shape) > 0 {
+		tour.Size = 0
+	}
+	tour.log.addf("Re-queued after a server restart (restart %d)", desc.Restarts)
+
+	ts.mu.Lock()
+	ts.tours[tour.ID] = tour
+	ts.mu.Unlock()
+}
+
+// createFromRecording registers an uploaded run as a finished tour.
+func (ts *tourStore) createFromRecording(rec *recording.Recording) *tourRecord {
+	return ts.addRecording(newID(), rec)
+}
+
+// addRecording registers a finished run under id.
+func (ts *tourStore) addRecording(id string, rec *recording.Recording) *tourRecord {
+	tour := &tourRecord{
+		ID:        id,
+		Size:      rec.Header.Size,
+		Shape:     rec.Header.Options.Shape,
+		StartPos:  rec.Header.StartPos,
+		Status:    statusFailed,
+		Result:    rec.Result(),
+		CreatedAt: time.Now(),
+		recording: rec,
+	}
+	if len(tour.Shape) > 0 {
+		tour.Size = 0
+	}
+	if rec.Summary.Success {
+		tour.Status = statusSolved
+		tour.computeMetrics()
+	}
+
+	ts.mu.Lock()
+	ts.tours[tour.ID] = tour
+	ts.mu.Unlock()
+	return tour
+}
+
+// finish records the outcome of a solve.
+func (ts *tourStore) finish(id, status string, result *solver.SolveResult) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if tour, ok := ts.tours[id]; ok {
+		tour.Status = status
+		tour.Result = result
+		if status == statusSolved {
+			tour.computeMetrics()
+		}
+	}
+}
+
+// exceedBudget marks a solve stopped by its memory budget as failed.
+func (ts *tourStore) exceedBudget(id string, result *solver.SolveResult, budgetErr *solver.MemoryBudgetError) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if tour, ok := ts.tours[id]; ok {
+		tour.Status = statusFailed
+		tour.Result = result
+		tour.MemoryBudget = budgetErr
+	}
+}
+
+// abandon marks a solve that will not be run again as failed, with the reason.
+func (ts *tourStore) abandon(id, reason string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if tour, ok := ts.tours[id]; ok {
+		tour.Status = statusFailed
+		tour.Error = reason
+		tour.log.addf("%s", reason)
+	}
+}
+
+// jobLog returns the log of a tour, or a detached one if the tour has none.
+func (ts *tourStore) jobLog(id string) *jobLog {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	if tour, ok := ts.tours[id]; ok && tour.log != nil {
+		return tour.log
+	}
+	return &jobLog{}
+}
+
+// attachTree stores the search tree recorded for a tour.
+func (ts *tourStore) attachTree(id string, tree *solver.SearchTree) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if tour, ok := ts.tours[id]; ok {
+		tour.tree = tree
+	}
+}
+
+// get returns a copy of the tour so callers can read it without holding the lock.
+func (ts *tourStore) get(id string) (tourRecord, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	tour, ok := ts.tours[id]
+	if !ok {
+		return tourRecord{}, false
+	}
+	return *tour, true
+}
+
+// list returns the summaries of the tours matching q.
+func (ts *tourStore) list(q tourQuery) []tourSummary {
+	ts.mu.RLock()
+	summaries := make([]tourSummary, 0, len(ts.tours))
+	for _, t := range ts.tours {
+		if (q.Status != "" && t.Status != q.Status) || (q.Size != 0 && t.Size != q.Size) {
+			continue
+		}
+		summaries = append(summaries, tourSummary{
+			ID:        t.ID,
+			Size:      t.Size,
+			Composite: len(t.Shape) > 0,
+			StartPos:  t.StartPos,
+			Status:    t.Status,
+			Metrics:   t.Metrics,
+			CreatedAt: t.CreatedAt,
+			rows:      t.rows(),
+		})
+	}
+	ts.mu.RUnlock()
+
+	less := tourSorts[q.SortBy]
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i].Metrics, summaries[j].Metrics
+		switch {
+		case less == nil:
+		case a != nil && b != nil:
+			if less(a, b) || less(b, a) {
+				return less(a, b)
+			}
+		case a != nil || b != nil:
+			return a != nil
+		}
+		// Ties and unsorted listings: newest first
+		return summaries[i].CreatedAt.After(summaries[j].CreatedAt)
+	})
+
+	if q.Limit > 0 && len(summaries) > q.Limit {
+		summaries = summaries[:q.Limit]
+	}
+	return summaries
+}
+
+// newID returns a random 16 character hex identifier for tours and requests.
+func newID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand never fails on supported platforms; fall back to the clock anyway
+		return hex.EncodeToString([]byte(time.Now().Format("150405.000000")))[:16]
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,36 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// handleTree exports the search tree recorded for a tour.
+// GET /api/tours/{id}/tree?format=json|dot
+func (s *Server) handleTree(w http.ResponseWriter, r *http.Request, tour tourRecord) {
+	if tour.tree == nil {
+		if tour.Status == statusSolving {
+			http.Error(w, "Tour is still solving", http.StatusConflict)
+			return
+		}
+		http.Error(w, "No search tree was recorded for this tour (start it with recordTree: true)", http.StatusNotFound)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "dot":
+		var buf bytes.Buffer
+		if err := tour.tree.WriteDOT(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		w.Write(buf.Bytes())
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tour.tree)
+	default:
+		http.Error(w, "format must be json or dot", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,54 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"the_knight/pkg/board"
+)
+
+// Limits of the boards /api/verify accepts.
+const (
+	maxVerifySide  = 200
+	maxVerifyBytes = 1 << 20
+)
+
+// handleVerify checks a board of move numbers (0 = unvisited, -1 = not a
+// square) against the board invariants, e.g. a tour solved elsewhere.
+// POST /api/verify {"board": [[1, 0, -1], ...]}
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Board board.Board `json:"board"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxVerifyBytes)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	rows, cols := req.Board.Dimensions()
+	if rows == 0 || cols == 0 || rows > maxVerifySide || cols > maxVerifySide {
+		http.Error(w, fmt.Sprintf("board must have between 1 and %d rows and columns", maxVerifySide), http.StatusBadRequest)
+		return
+	}
+
+	// An invalid board is a successful verification with a negative answer
+	resp := struct {
+		Valid bool   `json:"valid"`
+		Error string `json:"error,omitempty"`
+		// Complete is set when a valid board has every square visited
+		Complete bool `json:"complete"`
+	}{Valid: true}
+	if err := req.Board.CheckInvariants(); err != nil {
+		resp.Valid, resp.Error = false, err.Error()
+	} else {
+		resp.Complete = req.Board.IsComplete()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
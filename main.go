@@ -1,20 +1,15 @@
 package main
 
-// This is the legacy CLI entry point.
-// The new web server entry point is in cmd/server/main.go
-// Run with: go run cmd/server/main.go
+// This is the CLI entry point. Without arguments it starts the web server,
+// just like the legacy entry point did; see `the_knight help` for the other commands.
+// The plain web server entry point is in cmd/server/main.go
 
 import (
-	"fmt"
-	"the_knight/internal/web"
+	"os"
+
+	"the_knight/internal/cli"
 )
 
 func main() {
-	fmt.Println("Starting Knight's Tour Web Server...")
-	fmt.Println("Visit http://localhost:8080 in your browser")
-
-	server := web.NewServer()
-	if err := server.Start(":8080"); err != nil {
-		fmt.Printf("Server failed to start: %v\n", err)
-	}
+	os.Exit(cli.Run(os.Args[1:]))
 }
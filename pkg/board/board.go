@@ -1,15 +1,24 @@
 package board
 
 // Board represents a chess board as a 2D slice of integers.
-// Each cell stores the move number (0 = unvisited).
+// Each cell stores the move number (0 = unvisited, Blocked = not part of the board).
 type Board [][]int
 
+// Blocked marks cells of the bounding box that are not squares of a composite board.
+const Blocked = -1
+
 // Position represents a coordinate on the board.
 type Position struct {
 	X int
 	Y int
 }
 
+// knightMoves holds the offsets of all 8 possible knight moves.
+var knightMoves = [8]Position{
+	{2, -1}, {2, 1}, {-2, 1}, {-2, -1},
+	{1, 2}, {1, -2}, {-1, 2}, {-1, -2},
+}
+
 // NewBoard creates a new square board of the specified size, initialized with zeros.
 func NewBoard(size int) Board {
 	board := make(Board, size)
@@ -19,22 +28,23 @@ func NewBoard(size int) Board {
 	return board
 }
 
-// IsValidMove checks if a position is within bounds and unvisited.
-func (b Board) IsValidMove(pos Position) bool {
+// Contains reports whether a position is a square of the board. Besides the
+// bounds this excludes Blocked cells, so it also works for composite boards.
+func (b Board) Contains(pos Position) bool {
 	return pos.X >= 0 && pos.X < len(b) &&
-		pos.Y >= 0 && pos.Y < len(b[0]) &&
-		b[pos.X][pos.Y] == 0
+		pos.Y >= 0 && pos.Y < len(b[pos.X]) &&
+		b[pos.X][pos.Y] != Blocked
+}
+
+// IsValidMove checks if a position is on the board and unvisited.
+func (b Board) IsValidMove(pos Position) bool {
+	return b.Contains(pos) && b[pos.X][pos.Y] == 0
 }
 
 // CountValidMoves returns the number of valid knight moves from a given position.
 // This is used by Warnsdorff's heuristic.
 func (b Board) CountValidMoves(pos Position) int {
 	count := 0
-	knightMoves := []Position{
-		{2, -1}, {2, 1}, {-2, 1}, {-2, -1},
-		{1, 2}, {1, -2}, {-1, 2}, {-1, -2},
-	}
-
 	for _, move := range knightMoves {
 		newPos := Position{X: pos.X + move.X, Y: pos.Y + move.Y}
 		if b.IsValidMove(newPos) {
@@ -74,10 +84,31 @@ func (b Board) GetSize() int {
 	return len(b)
 }
 
-// GetCell returns the value at the specified position.
+// Dimensions returns the number of rows and columns of the board's bounding box.
+func (b Board) Dimensions() (rows, cols int) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	return len(b), len(b[0])
+}
+
+// SquareCount returns the number of squares on the board (Blocked cells excluded).
+func (b Board) SquareCount() int {
+	count := 0
+	for i := range b {
+		for j := range b[i] {
+			if b[i][j] != Blocked {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// GetCell returns the value at the specified position, or Blocked if it is not on the board.
 func (b Board) GetCell(pos Position) int {
-	if pos.X < 0 || pos.X >= len(b) || pos.Y < 0 || pos.Y >= len(b[0]) {
-		return -1
+	if !b.Contains(pos) {
+		return Blocked
 	}
 	return b[pos.X][pos.Y]
 }
@@ -0,0 +1,129 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rect is a rectangle of squares: rows X to X+Rows-1 and columns Y to Y+Cols-1.
+type Rect struct {
+	X    int `json:"x"`
+	Y    int `json:"y"`
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+}
+
+// maxCompositeSide bounds the rows and columns of the bounding box of a
+// composite board.
+const maxCompositeSide = 200
+
+// NewComposite builds a board from rectangles joined edge to edge. The
+// rectangles must not overlap and must form one connected piece where
+// neighbours share part of an edge. Cells of the bounding box outside every
+// rectangle are Blocked; knights may jump across them and across the seams.
+func NewComposite(rects []Rect) (Board, error) {
+	if len(rects) == 0 {
+		return nil, errors.New("composite board needs at least one rectangle")
+	}
+
+	rows, cols := 0, 0
+	for i, r := range rects {
+		if r.X < 0 || r.Y < 0 || r.Rows <= 0 || r.Cols <= 0 {
+			return nil, fmt.Errorf("rectangle %d: invalid geometry %+v", i, r)
+		}
+		// Compared one by one so the sums cannot overflow
+		if r.X > maxCompositeSide || r.Rows > maxCompositeSide || r.X+r.Rows > maxCompositeSide ||
+			r.Y > maxCompositeSide || r.Cols > maxCompositeSide || r.Y+r.Cols > maxCompositeSide {
+			return nil, fmt.Errorf("rectangle %d: exceeds the %dx%d limit of composite boards", i, maxCompositeSide, maxCompositeSide)
+		}
+		rows = max(rows, r.X+r.Rows)
+		cols = max(cols, r.Y+r.Cols)
+	}
+
+	b := make(Board, rows)
+	for i := range b {
+		b[i] = make([]int, cols)
+		for j := range b[i] {
+			b[i][j] = Blocked
+		}
+	}
+	for i, r := range rects {
+		for x := r.X; x < r.X+r.Rows; x++ {
+			for y := r.Y; y < r.Y+r.Cols; y++ {
+				if b[x][y] != Blocked {
+					return nil, fmt.Errorf("rectangle %d overlaps another rectangle at (%d, %d)", i, x, y)
+				}
+				b[x][y] = 0
+			}
+		}
+	}
+
+	if !joined(rects) {
+		return nil, errors.New("rectangles must be joined edge to edge into a single piece")
+	}
+	return b, nil
+}
+
+// joined reports whether the rectangles form one piece through shared edges.
+func joined(rects []Rect) bool {
+	seen := make([]bool, len(rects))
+	queue := []int{0}
+	seen[0] = true
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for j := range rects {
+			if !seen[j] && shareEdge(rects[i], rects[j]) {
+				seen[j] = true
+				queue = append(queue, j)
+			}
+		}
+	}
+	for _, ok := range seen {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// shareEdge reports whether two non-overlapping rectangles touch along a segment of positive length.
+func shareEdge(a, b Rect) bool {
+	overlap := func(lo1, hi1, lo2, hi2 int) bool { return max(lo1, lo2) < min(hi1, hi2) }
+	// Stacked vertically: a's bottom edge on b's top edge or vice versa
+	if a.X+a.Rows == b.X || b.X+b.Rows == a.X {
+		return overlap(a.Y, a.Y+a.Cols, b.Y, b.Y+b.Cols)
+	}
+	// Side by side
+	if a.Y+a.Cols == b.Y || b.Y+b.Cols == a.Y {
+		return overlap(a.X, a.X+a.Rows, b.X, b.X+b.Rows)
+	}
+	return false
+}
+
+// ShapeRects returns the rectangles of a named composite shape made of n x n blocks:
+//
+//	"L":    three blocks, two stacked and one to the right of the lower one
+//	"plus": five blocks, a center block with one on each side
+func ShapeRects(name string, n int) ([]Rect, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid block size %d", n)
+	}
+	switch name {
+	case "L":
+		return []Rect{
+			{X: 0, Y: 0, Rows: n, Cols: n},
+			{X: n, Y: 0, Rows: n, Cols: n},
+			{X: n, Y: n, Rows: n, Cols: n},
+		}, nil
+	case "plus":
+		return []Rect{
+			{X: 0, Y: n, Rows: n, Cols: n},
+			{X: n, Y: 0, Rows: n, Cols: n},
+			{X: n, Y: n, Rows: n, Cols: n},
+			{X: n, Y: 2 * n, Rows: n, Cols: n},
+			{X: 2 * n, Y: n, Rows: n, Cols: n},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown shape %q (want L or plus)", name)
+}
@@ -0,0 +1,61 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coordinate conventions for exchanging positions with users.
+const (
+	// CoordinatesMatrix addresses squares as Position{X: row, Y: column},
+	// with row 0 at the top. This is the convention used internally.
+	CoordinatesMatrix = "matrix"
+	// CoordinatesChess names squares like "e4": files a, b, c... from the
+	// left (Y) and ranks 1, 2, 3... from the bottom row (X). Boards wider than
+	// 26 files continue with aa, ab, ...
+	CoordinatesChess = "chess"
+)
+
+// IsValidCoordinates reports whether name is a known coordinate convention.
+// The empty string selects CoordinatesMatrix.
+func IsValidCoordinates(name string) bool {
+	return name == "" || name == CoordinatesMatrix || name == CoordinatesChess
+}
+
+// FileName returns the chess file letter(s) of a column: 0 is "a", 26 is "aa".
+func FileName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('a'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+// Square returns the chess name of a position on a board with the given
+// number of rows, e.g. Position{X: 7, Y: 0}.Square(8) is "a1".
+func (p Position) Square(rows int) string {
+	return FileName(p.Y) + strconv.Itoa(rows-p.X)
+}
+
+// ParseSquare converts a chess square name such as "e4" into a position on a
+// board with the given number of rows. It does not check the columns; use
+// Board.Contains for that.
+func ParseSquare(square string, rows int) (Position, error) {
+	s := strings.ToLower(strings.TrimSpace(square))
+	i := 0
+	col := 0
+	for i < len(s) && s[i] >= 'a' && s[i] <= 'z' {
+		col = col*26 + int(s[i]-'a') + 1
+		i++
+	}
+	if i == 0 || i > 3 || i == len(s) || s[i] < '0' || s[i] > '9' {
+		return Position{}, fmt.Errorf("invalid square %q", square)
+	}
+	rank, err := strconv.Atoi(s[i:])
+	if err != nil || rank < 1 || rank > rows {
+		return Position{}, fmt.Errorf("invalid square %q", square)
+	}
+	return Position{X: rows - rank, Y: col - 1}, nil
+}
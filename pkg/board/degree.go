@@ -0,0 +1,74 @@
+package board
+
+// DegreeBoard is a Board that keeps, for every square, the number of unvisited
+// squares a knight can reach from it. WriteToBoard and ClearPosition update
+// the counts of the (at most 8) affected neighbours, so CountValidMoves and
+// IsComplete answer in O(1) instead of rescanning the board.
+//
+// All changes must go through the DegreeBoard methods; writing to the
+// embedded Board directly leaves the counts stale.
+type DegreeBoard struct {
+	Board
+	degree [][]int
+	// unvisited is the number of squares not yet written to
+	unvisited int
+}
+
+// NewDegreeBoard wraps b and computes the initial counts from its current contents.
+func NewDegreeBoard(b Board) *DegreeBoard {
+	d := &DegreeBoard{Board: b, degree: make([][]int, len(b))}
+	for x := range b {
+		d.degree[x] = make([]int, len(b[x]))
+		for y := range b[x] {
+			pos := Position{X: x, Y: y}
+			if b[x][y] == 0 {
+				d.unvisited++
+			}
+			if b[x][y] != Blocked {
+				d.degree[x][y] = b.CountValidMoves(pos)
+			}
+		}
+	}
+	return d
+}
+
+// WriteToBoard marks a position with the given move number.
+func (d *DegreeBoard) WriteToBoard(pos Position, moveNumber int) {
+	if d.Board[pos.X][pos.Y] == 0 && moveNumber != 0 {
+		d.adjust(pos, -1)
+		d.unvisited--
+	}
+	d.Board[pos.X][pos.Y] = moveNumber
+}
+
+// ClearPosition resets a position to unvisited (0).
+func (d *DegreeBoard) ClearPosition(pos Position) {
+	if d.Board[pos.X][pos.Y] != 0 {
+		d.adjust(pos, 1)
+		d.unvisited++
+	}
+	d.Board[pos.X][pos.Y] = 0
+}
+
+// CountValidMoves returns the number of valid knight moves from a given position.
+func (d *DegreeBoard) CountValidMoves(pos Position) int {
+	if !d.Contains(pos) {
+		return 0
+	}
+	return d.degree[pos.X][pos.Y]
+}
+
+// IsComplete checks if all squares on the board have been visited.
+func (d *DegreeBoard) IsComplete() bool {
+	return d.unvisited == 0
+}
+
+// adjust adds delta to the counts of every square a knight reaches from pos.
+func (d *DegreeBoard) adjust(pos Position, delta int) {
+	for _, move := range knightMoves {
+		n := Position{X: pos.X + move.X, Y: pos.Y + move.Y}
+		if d.Contains(n) {
+			d.degree[n.X][n.Y] += delta
+		}
+	}
+}
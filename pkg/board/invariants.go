@@ -0,0 +1,57 @@
+package board
+
+import "fmt"
+
+// CheckInvariants returns an error describing the first way the board breaks
+// the invariants of a (partial) knight's tour, or nil if it keeps them all:
+//   - every row has the same number of cells;
+//   - every cell is Blocked, 0 (unvisited) or a move number in 1..SquareCount;
+//   - every move number is used once, and they run from 1 with no gaps;
+//   - every move is a knight's move from the one before it.
+//
+// A board with unvisited squares left can pass; IsComplete tells whether the
+// tour covers the whole board.
+func (b Board) CheckInvariants() error {
+	_, cols := b.Dimensions()
+	for x := range b {
+		if len(b[x]) != cols {
+			return fmt.Errorf("row %d has %d cells, want %d", x, len(b[x]), cols)
+		}
+	}
+
+	squares := b.SquareCount()
+	at := make([]Position, squares+1)
+	seen := make([]bool, squares+1)
+	last := 0
+	for x := range b {
+		for y, n := range b[x] {
+			switch {
+			case n == Blocked || n == 0:
+				continue
+			case n < 0 || n > squares:
+				return fmt.Errorf("move number %d at (%d, %d) is outside 1..%d", n, x, y, squares)
+			case seen[n]:
+				return fmt.Errorf("move number %d is used at both (%d, %d) and (%d, %d)", n, at[n].X, at[n].Y, x, y)
+			}
+			seen[n], at[n] = true, Position{X: x, Y: y}
+			last = max(last, n)
+		}
+	}
+
+	for n := 1; n <= last; n++ {
+		if !seen[n] {
+			return fmt.Errorf("move number %d is missing (moves run up to %d)", n, last)
+		}
+		if n > 1 && !isKnightMove(at[n-1], at[n]) {
+			return fmt.Errorf("move %d at (%d, %d) is not a knight's move from (%d, %d)",
+				n, at[n].X, at[n].Y, at[n-1].X, at[n-1].Y)
+		}
+	}
+	return nil
+}
+
+// isKnightMove reports whether a knight can jump directly between two squares.
+func isKnightMove(from, to Position) bool {
+	dx, dy := from.X-to.X, from.Y-to.Y
+	return dx*dx+dy*dy == 5
+}
@@ -0,0 +1,109 @@
+package board
+
+import "testing"
+
+// tour5 is a complete open tour of the 5x5 board.
+var tour5 = Board{
+	{1, 20, 9, 14, 3},
+	{10, 15, 2, 19, 24},
+	{21, 8, 25, 4, 13},
+	{16, 11, 6, 23, 18},
+	{7, 22, 17, 12, 5},
+}
+
+// fuzzBoard builds a board of up to 8x8 cells from fuzz input, one signed
+// byte per cell in row order; missing cells are unvisited.
+func fuzzBoard(rows, cols uint8, cells []byte) Board {
+	b := make(Board, 1+int(rows)%8)
+	n := 1 + int(cols)%8
+	for x := range b {
+		b[x] = make([]int, n)
+		for y := range b[x] {
+			if i := x*n + y; i < len(cells) {
+				b[x][y] = int(int8(cells[i]))
+			}
+		}
+	}
+	return b
+}
+
+// validPath checks the invariants independently of CheckInvariants: every
+// cell Blocked, unvisited or a move number, and the numbers 1..k each used
+// once, consecutive numbers a knight's move apart.
+func validPath(b Board) bool {
+	squares := 0
+	at := map[int]Position{}
+	for x := range b {
+		for y, v := range b[x] {
+			if v != Blocked {
+				squares++
+			}
+			if v == Blocked || v == 0 {
+				continue
+			}
+			if v < 0 {
+				return false
+			}
+			if _, dup := at[v]; dup {
+				return false
+			}
+			at[v] = Position{X: x, Y: y}
+		}
+	}
+	for n := 1; n <= len(at); n++ {
+		pos, ok := at[n]
+		if !ok || n > squares {
+			return false
+		}
+		if prev, ok := at[n-1]; ok {
+			dx, dy := pos.X-prev.X, pos.Y-prev.Y
+			if dx*dx+dy*dy != 5 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func FuzzCheckInvariants(f *testing.F) {
+	var cells []byte
+	for _, row := range tour5 {
+		for _, v := range row {
+			cells = append(cells, byte(v))
+		}
+	}
+	f.Add(uint8(4), uint8(4), cells)
+	f.Add(uint8(4), uint8(4), cells[:12])
+	f.Add(uint8(2), uint8(2), []byte{1, 0, 0, 0, 0, 0, 0, 2, 0})
+	f.Add(uint8(0), uint8(0), []byte{0xff})
+
+	f.Fuzz(func(t *testing.T, rows, cols uint8, cells []byte) {
+		b := fuzzBoard(rows, cols, cells)
+		err := b.CheckInvariants()
+		if want := validPath(b); (err == nil) != want {
+			t.Fatalf("CheckInvariants(%v) = %v, independent check says valid=%t", b, err, want)
+		}
+	})
+}
+
+func TestCheckInvariantsRejectsBrokenTours(t *testing.T) {
+	if err := tour5.CheckInvariants(); err != nil {
+		t.Fatalf("valid tour rejected: %v", err)
+	}
+	for name, mutate := range map[string]func(Board){
+		"swapped moves": func(b Board) { b[0][0], b[0][1] = b[0][1], b[0][0] },
+		"duplicate":     func(b Board) { b[0][1] = 1 },
+		"gap":           func(b Board) { b[2][2] = 0; b[0][4] = 0 },
+		"out of range":  func(b Board) { b[2][2] = 26 },
+		"ragged":        func(b Board) { b[4] = b[4][:4] },
+	} {
+		b := make(Board, len(tour5))
+		for i := range tour5 {
+			b[i] = append([]int(nil), tour5[i]...)
+		}
+		mutate(b)
+		if err := b.CheckInvariants(); err == nil {
+			t.Errorf("%s: CheckInvariants accepted %v", name, b)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tour
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"the_knight/internal/solver"
+	"the_knight/pkg/board"
+)
+
+// generateTries bounds the start squares Generate tries before giving up.
+const generateTries = 20
+
+// Generate returns a random complete tour of a size x size board, as a board
+// of move numbers, for property tests and fuzzing. The start square and the
+// tie-breaking seed are drawn from r, so a seeded r reproduces the tour.
+// Boards of size 2 to 4 have no tour and return an error.
+func Generate(r *rand.Rand, size int) (board.Board, error) {
+	if size < 1 || (size >= 2 && size <= 4) {
+		return nil, fmt.Errorf("no knight's tour exists on a %dx%d board", size, size)
+	}
+
+	for try := 0; try < generateTries; try++ {
+		start := board.Position{X: r.Intn(size), Y: r.Intn(size)}
+		opts := solver.SolveOptions{
+			// Seed 0 would mean the fixed move order
+			Seed: r.Int63() | 1,
+			// Some start squares (half of them on odd boards) have no tour;
+			// move on to another one instead of searching exhaustively
+			MaxAttempts: 16 * size * size,
+		}
+		result, err := solver.NewSolver().SolveWithOptions(context.Background(), size, start, opts)
+		if err == solver.ErrAttemptLimit || (err == nil && !result.Success) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		b := board.NewBoard(size)
+		for _, move := range result.Moves {
+			switch {
+			case move.IsDeadEnd:
+			case move.IsBacktrack:
+				b.ClearPosition(move.Position)
+			default:
+				b.WriteToBoard(move.Position, move.MoveNumber)
+			}
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("no tour found on a %dx%d board after %d start squares", size, size, generateTries)
+}
@@ -0,0 +1,30 @@
+package tour
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestGenerateProducesValidTours checks the property Generate promises: every
+// tour it returns passes the board invariants and covers the board.
+func TestGenerateProducesValidTours(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		size := []int{1, 5, 6, 8, 12}[seed%5]
+		b, err := Generate(r, size)
+		if err != nil {
+			t.Fatalf("seed %d, size %d: %v", seed, size, err)
+		}
+		if err := b.CheckInvariants(); err != nil {
+			t.Errorf("seed %d, size %d: %v", seed, size, err)
+		}
+		if !b.IsComplete() {
+			t.Errorf("seed %d, size %d: tour does not cover the board", seed, size)
+		}
+	}
+	for _, size := range []int{0, 2, 3, 4} {
+		if _, err := Generate(rand.New(rand.NewSource(1)), size); err == nil {
+			t.Errorf("size %d: want an error, there is no tour", size)
+		}
+	}
+}
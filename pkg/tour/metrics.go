@@ -0,0 +1,150 @@
+// Package tour works with knight's tours as ordered sequences of squares.
+package tour
+
+import (
+	"math"
+
+	"the_knight/pkg/board"
+)
+
+// Metrics describes how a completed tour looks when drawn as a path.
+type Metrics struct {
+	// Closed is true when the last square is a knight's move from the first.
+	// The closing move is then part of the drawing and of every metric below.
+	Closed bool `json:"closed"`
+	// Crossings counts pairs of moves whose segments intersect away from their endpoints.
+	Crossings int `json:"crossings"`
+	// PathLength is the total euclidean length of the drawn path, in squares.
+	PathLength float64 `json:"pathLength"`
+	// SymmetryScore is the fraction of moves whose 180° rotation about the
+	// board center is also a move of the tour (1 = perfectly symmetric).
+	SymmetryScore float64 `json:"symmetryScore"`
+	// QuarterTurnScore is the same for a 90° rotation.
+	QuarterTurnScore float64 `json:"quarterTurnScore"`
+}
+
+// segment is one drawn move, stored with its endpoints in canonical order.
+type segment struct {
+	a, b board.Position
+}
+
+func newSegment(p, q board.Position) segment {
+	if q.X < p.X || (q.X == p.X && q.Y < p.Y) {
+		p, q = q, p
+	}
+	return segment{a: p, b: q}
+}
+
+// ComputeMetrics measures a tour on a board of rows x cols squares (the
+// bounding box for composite boards). Symmetry is taken about the center of
+// that box; QuarterTurnScore is 0 unless the box is square.
+func ComputeMetrics(rows, cols int, path []board.Position) Metrics {
+	var m Metrics
+	if len(path) < 2 {
+		return m
+	}
+
+	segments := make([]segment, 0, len(path))
+	for i := 1; i < len(path); i++ {
+		segments = append(segments, newSegment(path[i-1], path[i]))
+	}
+	if isKnightMove(path[len(path)-1], path[0]) {
+		m.Closed = true
+		segments = append(segments, newSegment(path[len(path)-1], path[0]))
+	}
+
+	for _, s := range segments {
+		dx := float64(s.b.X - s.a.X)
+		dy := float64(s.b.Y - s.a.Y)
+		m.PathLength += math.Hypot(dx, dy)
+	}
+
+	m.Crossings = countCrossings(segments)
+	m.SymmetryScore = symmetryScore(segments, func(p board.Position) board.Position {
+		return board.Position{X: rows - 1 - p.X, Y: cols - 1 - p.Y}
+	})
+	if rows == cols {
+		m.QuarterTurnScore = symmetryScore(segments, func(p board.Position) board.Position {
+			return board.Position{X: p.Y, Y: rows - 1 - p.X}
+		})
+	}
+	return m
+}
+
+// countCrossings counts intersecting segment pairs. Knight moves span at most
+// 2 squares in each direction, so only segments whose lower corners are close
+// can meet; bucketing by that corner keeps this linear in the tour length.
+func countCrossings(segments []segment) int {
+	buckets := make(map[board.Position][]int, len(segments))
+	for i, s := range segments {
+		key := lowerCorner(s)
+		buckets[key] = append(buckets[key], i)
+	}
+
+	count := 0
+	for i, s := range segments {
+		key := lowerCorner(s)
+		for dx := -2; dx <= 2; dx++ {
+			for dy := -2; dy <= 2; dy++ {
+				for _, j := range buckets[board.Position{X: key.X + dx, Y: key.Y + dy}] {
+					if j > i && crosses(s, segments[j]) {
+						count++
+					}
+				}
+			}
+		}
+	}
+	return count
+}
+
+// lowerCorner returns the minimum corner of a segment's bounding box.
+func lowerCorner(s segment) board.Position {
+	return board.Position{X: min(s.a.X, s.b.X), Y: min(s.a.Y, s.b.Y)}
+}
+
+// crosses reports whether two segments properly intersect. Segments sharing an
+// endpoint (consecutive moves) never count.
+func crosses(s, t segment) bool {
+	if s.a == t.a || s.a == t.b || s.b == t.a || s.b == t.b {
+		return false
+	}
+	d1 := orientation(s.a, s.b, t.a)
+	d2 := orientation(s.a, s.b, t.b)
+	d3 := orientation(t.a, t.b, s.a)
+	d4 := orientation(t.a, t.b, s.b)
+	return d1*d2 < 0 && d3*d4 < 0
+}
+
+// orientation returns the sign of the cross product (b-a) x (c-a).
+func orientation(a, b, c board.Position) int {
+	v := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// symmetryScore is the fraction of segments whose image under transform is also a segment.
+func symmetryScore(segments []segment, transform func(board.Position) board.Position) float64 {
+	set := make(map[segment]struct{}, len(segments))
+	for _, s := range segments {
+		set[s] = struct{}{}
+	}
+
+	matched := 0
+	for _, s := range segments {
+		if _, ok := set[newSegment(transform(s.a), transform(s.b))]; ok {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(segments))
+}
+
+// isKnightMove reports whether a knight can jump directly between two squares.
+func isKnightMove(from, to board.Position) bool {
+	dx, dy := from.X-to.X, from.Y-to.Y
+	return dx*dx+dy*dy == 5
+}
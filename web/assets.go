@@ -0,0 +1,10 @@
+// Package web holds the browser assets of the server, embedded so the binary
+// runs without the source tree.
+package web
+
+import "embed"
+
+// Templates holds the HTML pages, under templates/.
+//
+//go:embed templates/*.html
+var Templates embed.FS